@@ -0,0 +1,46 @@
+package pokeapi
+
+import (
+	"context"
+	"strings"
+)
+
+type Ability struct {
+	EffectEntries []struct {
+		Effect      string           `json:"effect"`
+		Language    NamedAPIResource `json:"language"`
+		ShortEffect string           `json:"short_effect"`
+	} `json:"effect_entries"`
+	Generation   NamedAPIResource `json:"generation"`
+	ID           int              `json:"id"`
+	IsMainSeries bool             `json:"is_main_series"`
+	Name         string           `json:"name"`
+	Pokemon      []struct {
+		IsHidden bool             `json:"is_hidden"`
+		Pokemon  NamedAPIResource `json:"pokemon"`
+		Slot     int              `json:"slot"`
+	} `json:"pokemon"`
+}
+
+// Get an ability from the provided name
+func (c *Client) GetAbility(name string) (*Ability, error) {
+	return c.GetAbilityContext(context.Background(), name)
+}
+
+// GetAbility with a context that cancels the request
+func (c *Client) GetAbilityContext(ctx context.Context, name string) (*Ability, error) {
+	fullURL := c.endpointURL("ability", name)
+	return Get[Ability](ctx, c, fullURL)
+}
+
+// Full effect text in the given language, falling back to DefaultLanguage
+func (a *Ability) Effect(language string) string {
+	for _, lang := range fallbackLanguages(language) {
+		for _, entry := range a.EffectEntries {
+			if entry.Language.Name == lang {
+				return strings.Join(strings.Fields(entry.Effect), " ")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package pokeapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetAbility(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ability/static" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name":"static",
+			"effect_entries":[
+				{"effect":"Statik","short_effect":"","language":{"name":"de"}},
+				{"effect":"Whenever a move makes contact with this Pokémon,\nthe move's user has a 30% chance of being paralyzed.","short_effect":"","language":{"name":"en"}}
+			],
+			"pokemon":[
+				{"is_hidden":false,"slot":1,"pokemon":{"name":"pikachu"}},
+				{"is_hidden":true,"slot":3,"pokemon":{"name":"electrike"}}
+			]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+
+	ability, err := client.GetAbility("static")
+	if err != nil {
+		t.Fatalf("GetAbility returned error: %v", err)
+	}
+	if len(ability.Pokemon) != 2 || ability.Pokemon[0].IsHidden || !ability.Pokemon[1].IsHidden {
+		t.Errorf("unexpected pokemon %+v", ability.Pokemon)
+	}
+
+	expected := "Whenever a move makes contact with this Pokémon, the move's user has a 30% chance of being paralyzed."
+	if effect := ability.Effect("fr"); effect != expected {
+		t.Errorf("expected English fallback %q, got %q", expected, effect)
+	}
+	if effect := ability.Effect("de"); effect != "Statik" {
+		t.Errorf("expected German effect, got %q", effect)
+	}
+}
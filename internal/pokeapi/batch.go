@@ -0,0 +1,60 @@
+package pokeapi
+
+import (
+	"context"
+	"sync"
+)
+
+// Default number of concurrent fetches in a batch
+const DefaultConcurrency = 4
+
+// Outcome of one item of a batch, exactly one of Value and Err is set
+type BatchResult[T any] struct {
+	Value *T
+	Err   error
+}
+
+// Fetch every key with at most concurrency fetches in flight. Results are in
+// the same order as keys. Items not started before ctx is done fail with ctx.Err().
+func Batch[K, T any](ctx context.Context, keys []K, concurrency int, fetch func(context.Context, K) (*T, error)) []BatchResult[T] {
+	results := make([]BatchResult[T], len(keys))
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	concurrency = min(concurrency, len(keys))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				value, err := fetch(ctx, keys[i])
+				results[i] = BatchResult[T]{Value: value, Err: err}
+			}
+		}()
+	}
+
+	for i := range keys {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// Get many Pokemon at once using the client's concurrency. Requests still go
+// through the cache and rate limiter.
+func (c *Client) GetPokemonBatch(ctx context.Context, names []string) []BatchResult[Pokemon] {
+	return Batch(ctx, names, c.concurrency, c.GetPokemonContext)
+}
+
+// Get many moves at once using the client's concurrency
+func (c *Client) GetMoveBatch(ctx context.Context, names []string) []BatchResult[Move] {
+	return Batch(ctx, names, c.concurrency, c.GetMoveContext)
+}
@@ -0,0 +1,102 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	fetch := func(ctx context.Context, n int) (*int, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		if n%3 == 0 {
+			return nil, errors.New("divisible by three")
+		}
+		doubled := n * 2
+		return &doubled, nil
+	}
+
+	keys := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	results := Batch(context.Background(), keys, 3, fetch)
+
+	if len(results) != len(keys) {
+		t.Fatalf("expected %v results, got %v", len(keys), len(results))
+	}
+	for i, key := range keys {
+		if key%3 == 0 {
+			if results[i].Err == nil {
+				t.Errorf("expected error for %v", key)
+			}
+			continue
+		}
+		if results[i].Err != nil || *results[i].Value != key*2 {
+			t.Errorf("expected %v for %v, got %+v", key*2, key, results[i])
+		}
+	}
+	if peak.Load() > 3 {
+		t.Errorf("expected at most 3 fetches in flight, saw %v", peak.Load())
+	}
+}
+
+func TestBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	results := Batch(ctx, []string{"a", "b"}, 1, func(ctx context.Context, key string) (*string, error) {
+		calls++
+		return &key, nil
+	})
+	if calls != 0 {
+		t.Errorf("expected no fetches after cancel, got %v", calls)
+	}
+	for _, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", result.Err)
+		}
+	}
+}
+
+func TestGetPokemonBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/pokemon/")
+		if name == "missingno" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name":"` + name + `"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithConcurrency(2))
+	defer client.Close()
+
+	names := []string{"bulbasaur", "missingno", "squirtle"}
+	results := client.GetPokemonBatch(context.Background(), names)
+	for i, name := range names {
+		if name == "missingno" {
+			if !errors.Is(results[i].Err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound for missingno, got %v", results[i].Err)
+			}
+			continue
+		}
+		if results[i].Err != nil || results[i].Value.Name != name {
+			t.Errorf("expected %v, got %+v", name, results[i])
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package pokeapi
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Name of the file holding each resource in a bundle, as in PokeAPI's api-data
+const bundleIndex = "index.json"
+
+// Default page size of list endpoints when the request sets no limit
+const defaultListLimit = 20
+
+// A local copy of PokeAPI responses in PokeAPI's JSON layout, where the
+// response for a URL path such as /api/v2/pokemon/pikachu/ is stored at
+// api/v2/pokemon/pikachu/index.json. Lists are stored whole and paged on read.
+type Bundle struct {
+	fsys   fs.FS
+	closer io.Closer // nil when there is nothing to close
+}
+
+// Serve responses from fsys, which is laid out as described on Bundle
+func NewBundle(fsys fs.FS) *Bundle {
+	return &Bundle{fsys: fsys}
+}
+
+// Open a bundle from a directory or a .zip archive of one
+func OpenBundle(name string) (*Bundle, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, fmt.Errorf("error opening bundle %v: %v", name, err)
+	}
+	if info.IsDir() {
+		return NewBundle(os.DirFS(name)), nil
+	}
+
+	archive, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, fmt.Errorf("error opening bundle %v: %v", name, err)
+	}
+	return &Bundle{fsys: archive, closer: archive}, nil
+}
+
+// Releases the archive of a bundle opened from a .zip
+func (b *Bundle) Close() error {
+	if b.closer == nil {
+		return nil
+	}
+	return b.closer.Close()
+}
+
+// Implements http.RoundTripper so a Bundle can stand in for the network.
+// Missing resources fail with a *NotInBundleError.
+func (b *Bundle) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	if req.Method != http.MethodGet {
+		return nil, fmt.Errorf("error bundle only serves GET requests, got %v", req.Method)
+	}
+
+	name, ok := resourcePath(req.URL)
+	if !ok {
+		return nil, &NotInBundleError{URL: req.URL.String(), Path: bundlePath(req.URL.EscapedPath())}
+	}
+	body, err := fs.ReadFile(b.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &NotInBundleError{URL: req.URL.String(), Path: name}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle file %v: %w: %v", name, ErrBadBundle, err)
+	}
+
+	body, err = pageList(body, req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle file %v: %w: %v", name, ErrBadBundle, err)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Bundle file holding the response for a URL path
+func bundlePath(urlPath string) string {
+	return path.Join(strings.Trim(urlPath, "/"), bundleIndex)
+}
+
+// Bundle file holding the response for u, built segment by segment from the
+// escaped path so a name such as "..%2Fpokemon" cannot decode into another
+// resource. Not ok when a segment is "." or ".." or holds an escaped '/',
+// which PokeAPI never serves.
+func resourcePath(u *url.URL) (string, bool) {
+	segments := strings.Split(strings.Trim(u.EscapedPath(), "/"), "/")
+	for i, segment := range segments {
+		name, err := url.PathUnescape(segment)
+		if err != nil || name == "." || name == ".." || strings.Contains(name, "/") {
+			return "", false
+		}
+		segments[i] = name
+	}
+	return path.Join(append(segments, bundleIndex)...), true
+}
+
+// Cuts the page requested by the limit and offset of u out of a whole list.
+// Bodies that are not lists are returned unchanged.
+func pageList(body []byte, u *url.URL) ([]byte, error) {
+	var list struct {
+		Count   *int               `json:"count"`
+		Results []NamedAPIResource `json:"results"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, err
+	}
+	if list.Count == nil || list.Results == nil {
+		return body, nil
+	}
+
+	query := u.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	total := len(list.Results)
+	start := min(offset, total)
+	end := min(offset+limit, total)
+	page := NamedAPIResourceList{
+		Count:   total,
+		Results: list.Results[start:end],
+	}
+	if end < total {
+		page.Next = pageURL(u, limit, end)
+	}
+	if start > 0 {
+		page.Previous = pageURL(u, limit, max(start-limit, 0))
+	}
+	return json.Marshal(page)
+}
+
+// URL of another page of the same list
+func pageURL(u *url.URL, limit, offset int) *string {
+	next := *u
+	query := next.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	next.RawQuery = query.Encode()
+	link := next.String()
+	return &link
+}
+
+// Write every resource of a list, up to limit when limit > 0, into a bundle
+// directory along with the list itself. Resources are stored under their name
+// and their ID so both kinds of URL resolve. Returns how many were written.
+func (c *Client) BuildBundle(ctx context.Context, dir, resource string, limit int) (int, error) {
+	var items []NamedAPIResource
+	for item, err := range c.List(ctx, resource, ListOptions{Limit: 100}) {
+		if err != nil {
+			return 0, err
+		}
+		items = append(items, item)
+		if limit > 0 && len(items) >= limit {
+			break
+		}
+	}
+
+	fetch := func(ctx context.Context, item NamedAPIResource) (*[]byte, error) {
+		body, err := c.FetchBytesContext(ctx, item.URL)
+		return &body, err
+	}
+	for i, result := range Batch(ctx, items, c.concurrency, fetch) {
+		if result.Err != nil {
+			return 0, result.Err
+		}
+		item := items[i]
+		if err := writeBundleFile(dir, item.URL, *result.Value); err != nil {
+			return 0, err
+		}
+		if item.Name == "" {
+			continue
+		}
+		if err := writeBundleFile(dir, c.endpointURL(resource, item.Name), *result.Value); err != nil {
+			return 0, err
+		}
+	}
+
+	list, err := json.Marshal(NamedAPIResourceList{Count: len(items), Results: items})
+	if err != nil {
+		return 0, fmt.Errorf("error encoding %v list: %v", resource, err)
+	}
+	if err := writeBundleFile(dir, c.endpointURL(resource, ""), list); err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// Write body to the bundle file for rawURL under dir
+func writeBundleFile(dir, rawURL string, body []byte) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("error parsing url %v: %v", rawURL, err)
+	}
+	// Paths come from the server, never write outside dir
+	local := filepath.FromSlash(bundlePath(u.Path))
+	if !filepath.IsLocal(local) {
+		return fmt.Errorf("error bundling %v: path is outside the bundle", rawURL)
+	}
+	name := filepath.Join(dir, local)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("error creating bundle directory: %v", err)
+	}
+	if err := os.WriteFile(name, body, 0o644); err != nil {
+		return fmt.Errorf("error writing bundle file %v: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package pokeapi
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	var list NamedAPIResourceList
+	for i, name := range []string{"bulbasaur", "ivysaur", "venusaur"} {
+		list.Results = append(list.Results, NamedAPIResource{Name: name, URL: fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%v/", i+1)})
+	}
+	list.Count = len(list.Results)
+	listJSON, _ := json.Marshal(list)
+
+	fsys := fstest.MapFS{
+		"api/v2/pokemon/index.json":         {Data: listJSON},
+		"api/v2/pokemon/pikachu/index.json": {Data: []byte(`{"name":"pikachu","base_experience":112}`)},
+	}
+	attempts := 0
+	client := NewClient(
+		WithBundle(NewBundle(fsys)),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, OnAttempt: func(Attempt) { attempts++ }}),
+	)
+	defer client.Close()
+
+	if !client.Offline() {
+		t.Errorf("expected client to be offline")
+	}
+
+	pokemon, err := client.GetPokemon("pikachu")
+	if err != nil || pokemon.Name != "pikachu" || pokemon.BaseExperience != 112 {
+		t.Fatalf("expected pikachu from bundle, got %+v, %v", pokemon, err)
+	}
+
+	page, err := client.GetResourceList(client.ListURL("pokemon", ListOptions{Limit: 2}))
+	if err != nil {
+		t.Fatalf("GetResourceList returned error: %v", err)
+	}
+	if page.Count != 3 || len(page.Results) != 2 || page.Next == nil || page.Previous != nil {
+		t.Fatalf("expected first page of 2 with a next link, got %+v", page)
+	}
+	page, err = client.GetResourceList(*page.Next)
+	if err != nil {
+		t.Fatalf("GetResourceList returned error: %v", err)
+	}
+	if len(page.Results) != 1 || page.Results[0].Name != "venusaur" || page.Next != nil || page.Previous == nil {
+		t.Fatalf("expected last page with venusaur, got %+v", page)
+	}
+
+	attempts = 0
+	_, err = client.GetPokemon("mew")
+	var notInBundle *NotInBundleError
+	if !errors.Is(err, ErrNotInBundle) || !errors.As(err, &notInBundle) {
+		t.Fatalf("expected ErrNotInBundle, got %v", err)
+	}
+	if notInBundle.Path != "api/v2/pokemon/mew/index.json" {
+		t.Errorf("expected lookup of api/v2/pokemon/mew/index.json, got %v", notInBundle.Path)
+	}
+	if attempts != 1 {
+		t.Errorf("expected missing resources not to be retried, got %v attempts", attempts)
+	}
+}
+
+func TestBuildBundle(t *testing.T) {
+	names := []string{"bulbasaur", "ivysaur", "venusaur"}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pokemon/" {
+			list := NamedAPIResourceList{Count: len(names)}
+			for i, name := range names {
+				list.Results = append(list.Results, NamedAPIResource{Name: name, URL: fmt.Sprintf("%v/pokemon/%v/", server.URL, i+1)})
+			}
+			json.NewEncoder(w).Encode(list)
+			return
+		}
+		var id int
+		if _, err := fmt.Sscanf(r.URL.Path, "/pokemon/%d/", &id); err != nil || id < 1 || id > len(names) {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"id":%v,"name":%q}`, id, names[id-1])
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(0, 0))
+	defer client.Close()
+
+	dir := t.TempDir()
+	n, err := client.BuildBundle(context.Background(), dir, "pokemon", 2)
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 resources bundled, got %v, %v", n, err)
+	}
+
+	bundle, err := OpenBundle(dir)
+	if err != nil {
+		t.Fatalf("OpenBundle returned error: %v", err)
+	}
+	offline := NewClient(WithBaseURL(server.URL), WithBundle(bundle))
+	defer offline.Close()
+
+	for _, name := range []string{"ivysaur", "2"} {
+		pokemon, err := offline.GetPokemon(name)
+		if err != nil || pokemon.Name != "ivysaur" {
+			t.Errorf("expected ivysaur for %v, got %+v, %v", name, pokemon, err)
+		}
+	}
+	if _, err := offline.GetPokemon("venusaur"); !errors.Is(err, ErrNotInBundle) {
+		t.Errorf("expected venusaur beyond the limit to be missing, got %v", err)
+	}
+	page, err := offline.GetResourceList(offline.ListURL("pokemon", ListOptions{}))
+	if err != nil || page.Count != 2 {
+		t.Errorf("expected bundled list of 2, got %+v, %v", page, err)
+	}
+}
+
+func TestOpenBundleZip(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "bundle.zip")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(f)
+	w, err := archive.Create("api/v2/pokemon/pikachu/index.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(`{"name":"pikachu"}`))
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	bundle, err := OpenBundle(name)
+	if err != nil {
+		t.Fatalf("OpenBundle returned error: %v", err)
+	}
+	client := NewClient(WithBundle(bundle))
+	defer client.Close()
+
+	pokemon, err := client.GetPokemon("pikachu")
+	if err != nil || pokemon.Name != "pikachu" {
+		t.Fatalf("expected pikachu from zip bundle, got %+v, %v", pokemon, err)
+	}
+
+	if _, err := OpenBundle(filepath.Join(t.TempDir(), "missing")); err == nil || !strings.Contains(err.Error(), "error opening bundle") {
+		t.Errorf("expected error opening missing bundle, got %v", err)
+	}
+}
+
+func TestWriteBundleFileOutsideDir(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "bundle")
+	for _, rawURL := range []string{
+		"https://pokeapi.co/../../evil",
+		"https://pokeapi.co/api/v2/../../../evil",
+	} {
+		if err := writeBundleFile(dir, rawURL, []byte("{}")); err == nil {
+			t.Errorf("expected error writing %v", rawURL)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(parent, "evil")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected nothing written outside the bundle, got %v", err)
+	}
+
+	if err := writeBundleFile(dir, "https://pokeapi.co/api/v2/pokemon/../type/fire/", []byte("{}")); err != nil {
+		t.Errorf("expected paths that stay inside the bundle to be written, got %v", err)
+	}
+}
+
+func TestBundleEscapedNames(t *testing.T) {
+	pikachu := []byte(`{"name":"pikachu","base_experience":112}`)
+	fsys := fstest.MapFS{
+		"api/v2/pokemon/pikachu/index.json": {Data: pikachu},
+	}
+	// PokeAPI matches the escaped path, so an escaped '/' never reaches another resource
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/api/v2/pokemon/pikachu/" || r.URL.EscapedPath() == "/api/v2/pokemon/pikachu" {
+			w.Write(pikachu)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	retry := WithRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	online := NewClient(WithBaseURL(server.URL+"/api/v2/"), retry)
+	defer online.Close()
+	offline := NewClient(WithBundle(NewBundle(fsys)), retry)
+	defer offline.Close()
+
+	cases := []struct {
+		resource    string
+		name        string
+		expectFound bool
+	}{
+		{resource: "pokemon", name: "pikachu", expectFound: true},
+		{resource: "location-area", name: "../pokemon/pikachu"},
+		{resource: "location-area", name: "./../pokemon/pikachu"},
+		{resource: "pokemon", name: ".."},
+		{resource: "pokemon", name: "pikachu/"},
+	}
+	for _, c := range cases {
+		_, onlineErr := online.FetchBytes(online.endpointURL(c.resource, c.name))
+		_, offlineErr := offline.FetchBytes(offline.endpointURL(c.resource, c.name))
+		if (onlineErr == nil) != c.expectFound {
+			t.Errorf("expected online %v %q found %v, got %v", c.resource, c.name, c.expectFound, onlineErr)
+		}
+		if (offlineErr == nil) != c.expectFound {
+			t.Errorf("expected offline %v %q found %v, got %v", c.resource, c.name, c.expectFound, offlineErr)
+		}
+		if !c.expectFound && !errors.Is(offlineErr, ErrNotInBundle) {
+			t.Errorf("expected ErrNotInBundle for %q, got %v", c.name, offlineErr)
+		}
+	}
+}
+
+func TestBundleBadFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api/v2/pokemon/index.json": {Data: []byte("not json")},
+	}
+	attempts := 0
+	client := NewClient(
+		WithBundle(NewBundle(fsys)),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, OnAttempt: func(Attempt) { attempts++ }}),
+	)
+	defer client.Close()
+
+	_, err := client.GetResourceList(client.ListURL("pokemon", ListOptions{}))
+	if !errors.Is(err, ErrBadBundle) {
+		t.Fatalf("expected ErrBadBundle, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected bad bundle files not to be retried, got %v attempts", attempts)
+	}
+}
@@ -1,213 +1,494 @@
 package pokeapi
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/evanwiseman/pokedexcli/internal/pokecache"
 )
 
 const (
-	BaseURL         = "https://pokeapi.co/api/v2/"
-	LocationAreaURL = BaseURL + "location-area/"
-	PokemonURL      = BaseURL + "pokemon/"
-	ReapInterval    = 30
+	DefaultBaseURL      = "https://pokeapi.co/api/v2/"
+	DefaultUserAgent    = "pokedexcli"
+	DefaultReapInterval = 30 * time.Second
+	DefaultTimeout      = 30 * time.Second
+	DefaultCacheBytes   = 64 << 20 // a few hundred Pokemon payloads
 )
 
-type Config struct {
-	Next     *string
-	Previous *string
+type Client struct {
+	httpClient  *http.Client
+	cache       pokecache.Store
+	baseURL     string
+	userAgent   string
+	retry       RetryPolicy
+	ownsHTTP    bool         // httpClient was built by NewClient
+	bundle      *Bundle      // nil unless offline
+	limiter     *RateLimiter // nil when rate limiting is disabled
+	logger      *log.Logger  // nil when debug output is disabled
+	concurrency int          // workers used by batch fetches
+	flights     flightGroup  // in-flight requests by url
+
+	evolutions   map[string]*EvolutionChain // decoded chains by species name
+	evolutionsMu sync.Mutex
 }
 
-type Client struct {
-	httpClient *http.Client
-	cache      *pokecache.Cache
+// Configures a Client built by NewClient
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	baseURL       string
+	httpClient    *http.Client
+	timeout       time.Duration
+	userAgent     string
+	cacheInterval time.Duration
+	cacheOpts     []pokecache.Option
+	disk          *pokecache.DiskCache
+	store         pokecache.Store
+	retry         RetryPolicy
+	rps           float64
+	burst         int
+	logger        *log.Logger
+	concurrency   int
+	bundle        *Bundle
+}
+
+// Use a different PokeAPI base URL, e.g. a self-hosted mirror or test server
+func WithBaseURL(baseURL string) Option {
+	return func(o *clientOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+// Use the provided http.Client instead of building one
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// Set the timeout of the http.Client, 0 means no timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// Set the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// Set how long responses stay in the cache, 0 or less keeps them until evicted
+func WithCacheInterval(interval time.Duration) Option {
+	return func(o *clientOptions) {
+		o.cacheInterval = interval
+	}
+}
+
+// Pass options such as size limits to the response cache
+func WithCacheOptions(opts ...pokecache.Option) Option {
+	return func(o *clientOptions) {
+		o.cacheOpts = append(o.cacheOpts, opts...)
+	}
+}
+
+// Layer a persistent disk cache beneath the in-memory cache
+func WithDiskCache(disk *pokecache.DiskCache) Option {
+	return func(o *clientOptions) {
+		o.disk = disk
+	}
+}
+
+// Cache responses in store instead of the default in-memory cache. The
+// other cache options are ignored and the client closes store on Close.
+func WithStore(store pokecache.Store) Option {
+	return func(o *clientOptions) {
+		o.store = store
+	}
+}
+
+// Set how failed requests are retried, see RetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retry = policy
+	}
+}
+
+// Limit network requests to rps per second with bursts of up to burst.
+// Cache hits are never limited. An rps of 0 or less disables the limiter.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(o *clientOptions) {
+		o.rps = rps
+		o.burst = burst
+	}
+}
+
+// Write debug output, such as rate limiter waits, to logger
+func WithDebugLogger(logger *log.Logger) Option {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// Set how many requests batch fetches such as GetPokemonBatch run at once
+func WithConcurrency(n int) Option {
+	return func(o *clientOptions) {
+		o.concurrency = n
+	}
+}
+
+// Serve every request from bundle instead of the network. The rate limit and
+// http.Client options are ignored and the client closes bundle on Close.
+func WithBundle(bundle *Bundle) Option {
+	return func(o *clientOptions) {
+		o.bundle = bundle
+	}
 }
 
 // Creates a new http Client and Cache
-func NewClient() *Client {
+func NewClient(opts ...Option) *Client {
+	o := clientOptions{
+		baseURL:       DefaultBaseURL,
+		timeout:       DefaultTimeout,
+		userAgent:     DefaultUserAgent,
+		cacheInterval: DefaultReapInterval,
+		retry:         DefaultRetryPolicy,
+		rps:           DefaultRequestsPerSecond,
+		burst:         DefaultBurst,
+		concurrency:   DefaultConcurrency,
+		cacheOpts:     []pokecache.Option{pokecache.WithMaxBytes(DefaultCacheBytes)},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	httpClient := o.httpClient
+	if o.bundle != nil {
+		httpClient = &http.Client{Transport: o.bundle}
+		o.rps = 0
+	}
+	ownsHTTP := httpClient == nil
+	if ownsHTTP {
+		httpClient = &http.Client{Timeout: o.timeout}
+	}
+
+	store := o.store
+	if store == nil {
+		store = pokecache.NewCache(o.cacheInterval, o.cacheOpts...)
+		if o.disk != nil {
+			store = pokecache.NewTiered(store, o.disk)
+		}
+	}
+
+	var limiter *RateLimiter
+	if o.rps > 0 {
+		limiter = NewRateLimiter(o.rps, o.burst)
+	}
+
 	return &Client{
-		httpClient: &http.Client{},
-		cache:      pokecache.NewCache(ReapInterval * time.Second),
+		httpClient:  httpClient,
+		cache:       store,
+		baseURL:     normalizeBaseURL(o.baseURL),
+		userAgent:   o.userAgent,
+		retry:       o.retry,
+		ownsHTTP:    ownsHTTP,
+		bundle:      o.bundle,
+		limiter:     limiter,
+		logger:      o.logger,
+		concurrency: o.concurrency,
+		evolutions:  make(map[string]*EvolutionChain),
+	}
+}
+
+// Closes the cache store and closes idle connections of an http.Client built
+// by NewClient. The client must not be used after Close.
+func (c *Client) Close() error {
+	if c.ownsHTTP {
+		c.httpClient.CloseIdleConnections()
 	}
+	if c.bundle != nil {
+		if err := c.bundle.Close(); err != nil {
+			c.cache.Close()
+			return err
+		}
+	}
+	return c.cache.Close()
+}
+
+// Reports whether requests are served from a bundle, see WithBundle
+func (c *Client) Offline() bool {
+	return c.bundle != nil
+}
+
+// Print debug output when a debug logger is configured
+func (c *Client) debugf(format string, args ...any) {
+	if c.logger != nil {
+		c.logger.Printf(format, args...)
+	}
+}
+
+// Base URLs always end in a single '/' so endpoints can be appended
+func normalizeBaseURL(baseURL string) string {
+	return strings.TrimRight(baseURL, "/") + "/"
+}
+
+// Base URL all endpoint URLs are derived from
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// URL of the first page of the location-area list
+func (c *Client) LocationAreaURL() string {
+	return c.endpointURL("location-area", "")
+}
+
+// Build the URL of a resource endpoint, optionally for a named resource
+func (c *Client) endpointURL(resource, name string) string {
+	return c.baseURL + resource + "/" + url.PathEscape(name)
 }
 
 // Fetch bytes ([]byte) from a url using the client. Store bytes in cache.
+// Non-2xx responses return a *StatusError and are not cached.
 func (c *Client) FetchBytes(url string) ([]byte, error) {
+	return c.FetchBytesContext(context.Background(), url)
+}
+
+// FetchBytes with a context that cancels the request
+func (c *Client) FetchBytesContext(ctx context.Context, url string) ([]byte, error) {
 	bytes, ok := c.cache.Get(url)
 	if ok {
 		return bytes, nil
 	}
 
-	res, err := c.httpClient.Get(url)
+	// Concurrent callers for the same url share one request and one cache write
+	return c.flights.do(ctx, url, func(ctx context.Context) ([]byte, error) {
+		return c.fetchAndStore(ctx, url)
+	})
+}
+
+// Fetch url and store it in the cache. An expired entry with validators is
+// revalidated and reused if PokeAPI answers 304 Not Modified.
+func (c *Client) fetchAndStore(ctx context.Context, url string) ([]byte, error) {
+	revalidator, canRevalidate := c.cache.(pokecache.Revalidator)
+	var stale []byte
+	var validators pokecache.Validators
+	if canRevalidate {
+		var ok bool
+		stale, validators, ok = revalidator.GetStale(url)
+		if !ok {
+			validators = pokecache.Validators{}
+		}
+	}
+
+	res, err := c.getWithRetry(ctx, url, validators)
+	if err != nil {
+		return nil, err
+	}
+	if res.notModified {
+		c.debugf("revalidated %v", url)
+		revalidator.Refresh(url)
+		return stale, nil
+	}
+
+	if canRevalidate {
+		revalidator.AddWithValidators(url, res.body, res.validators)
+	} else {
+		c.cache.Add(url, res.body)
+	}
+	return res.body, nil
+}
+
+// Usage of each of the client's cache stores, fastest first
+func (c *Client) CacheStats() ([]pokecache.Stats, error) {
+	return pokecache.StoreStats(c.cache)
+}
+
+// Empty the client's cache, along with its decoded evolution chains
+func (c *Client) ClearCache() error {
+	c.evolutionsMu.Lock()
+	clear(c.evolutions)
+	c.evolutionsMu.Unlock()
+
+	if clearer, ok := c.cache.(pokecache.Clearer); ok {
+		return clearer.Clear()
+	}
+	return fmt.Errorf("cache %T cannot be cleared", c.cache)
+}
+
+// Successful result of a request
+type response struct {
+	body        []byte
+	validators  pokecache.Validators
+	notModified bool // 304 to a conditional request, body is empty
+}
+
+// Build a GET request for url with the client's headers, conditional on
+// validators when they are set
+func (c *Client) newRequest(ctx context.Context, url string, validators pokecache.Validators) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error get url %v: %v", url, err)
+		return nil, fmt.Errorf("error creating request %v: %v", url, err)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+	return req, nil
+}
+
+// Perform a single request, returns a *StatusError for non-2xx responses
+// other than 304 Not Modified
+func (c *Client) do(req *http.Request) (response, error) {
+	url := req.URL.String()
+	if c.limiter != nil {
+		waited, err := c.limiter.Wait(req.Context())
+		if err != nil {
+			return response{}, err
+		}
+		if waited > 0 {
+			c.debugf("rate limiter waited %v before %v", waited, url)
+		}
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return response{}, fmt.Errorf("error get url %v: %w", url, err)
 	}
 	defer res.Body.Close()
 
+	conditional := req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+	if res.StatusCode == http.StatusNotModified && conditional {
+		io.Copy(io.Discard, res.Body)
+		return response{notModified: true}, nil
+	}
+
+	// Error responses are never cached so a retry can succeed
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		io.Copy(io.Discard, res.Body)
+		statusErr := &StatusError{StatusCode: res.StatusCode, URL: url}
+		if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+			statusErr.RetryAfter = parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+		}
+		return response{}, statusErr
+	}
+
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error reading body: %v", err)
+		return response{}, fmt.Errorf("error reading body: %w", err)
 	}
-	c.cache.Add(url, body)
-	return body, nil
+	return response{
+		body: body,
+		validators: pokecache.Validators{
+			ETag:         res.Header.Get("ETag"),
+			LastModified: res.Header.Get("Last-Modified"),
+		},
+	}, nil
 }
 
 type LocationArea struct {
 	EncounterMethodRates []struct {
-		EncounterMethod struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"encounter_method"`
-		VersionDetails []struct {
-			Rate    int `json:"rate"`
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
+		EncounterMethod NamedAPIResource `json:"encounter_method"`
+		VersionDetails  []struct {
+			Rate    int              `json:"rate"`
+			Version NamedAPIResource `json:"version"`
 		} `json:"version_details"`
 	} `json:"encounter_method_rates"`
-	GameIndex int `json:"game_index"`
-	ID        int `json:"id"`
-	Location  struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"location"`
-	Name  string `json:"name"`
-	Names []struct {
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-		Name string `json:"name"`
+	GameIndex int              `json:"game_index"`
+	ID        int              `json:"id"`
+	Location  NamedAPIResource `json:"location"`
+	Name      string           `json:"name"`
+	Names     []struct {
+		Language NamedAPIResource `json:"language"`
+		Name     string           `json:"name"`
 	} `json:"names"`
 	PokemonEncounters []struct {
-		Pokemon struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"pokemon"`
+		Pokemon        NamedAPIResource `json:"pokemon"`
 		VersionDetails []struct {
 			EncounterDetails []struct {
-				Chance          int   `json:"chance"`
-				ConditionValues []any `json:"condition_values"`
-				MaxLevel        int   `json:"max_level"`
-				Method          struct {
-					Name string `json:"name"`
-					URL  string `json:"url"`
-				} `json:"method"`
-				MinLevel int `json:"min_level"`
+				Chance          int              `json:"chance"`
+				ConditionValues []any            `json:"condition_values"`
+				MaxLevel        int              `json:"max_level"`
+				Method          NamedAPIResource `json:"method"`
+				MinLevel        int              `json:"min_level"`
 			} `json:"encounter_details"`
-			MaxChance int `json:"max_chance"`
-			Version   struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
+			MaxChance int              `json:"max_chance"`
+			Version   NamedAPIResource `json:"version"`
 		} `json:"version_details"`
 	} `json:"pokemon_encounters"`
 }
 
 // Get the location-area from the provided name.
 func (c *Client) GetLocationArea(name string) (*LocationArea, error) {
-	fullURL := LocationAreaURL + name
-	bytes, err := c.FetchBytes(fullURL)
-	if err != nil {
-		return nil, err
-	}
-
-	var area LocationArea
-	err = json.Unmarshal(bytes, &area)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling bytes: %v", err)
-	}
-	return &area, nil
+	return c.GetLocationAreaContext(context.Background(), name)
 }
 
-type LocationAreaList struct {
-	Count    int     `json:"count"`
-	Next     *string `json:"next"`
-	Previous *string `json:"previous"`
-	Results  []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"results"`
+// GetLocationArea with a context that cancels the request
+func (c *Client) GetLocationAreaContext(ctx context.Context, name string) (*LocationArea, error) {
+	fullURL := c.endpointURL("location-area", name)
+	return Get[LocationArea](ctx, c, fullURL)
 }
 
-// Get a location-area list from a url. Should start at LocationAreaURL
+// Location areas are listed like any other resource
+type LocationAreaList = NamedAPIResourceList
+
+// Get a location-area list from a url. Should start at Client.LocationAreaURL
 func (c *Client) GetLocationAreaList(fullURL string) (*LocationAreaList, error) {
-	bytes, err := c.FetchBytes(fullURL)
-	if err != nil {
-		return nil, err
-	}
+	return c.GetResourceListContext(context.Background(), fullURL)
+}
 
-	var areas LocationAreaList
-	err = json.Unmarshal(bytes, &areas)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling bytes: %v", err)
-	}
-	return &areas, nil
+// GetLocationAreaList with a context that cancels the request
+func (c *Client) GetLocationAreaListContext(ctx context.Context, fullURL string) (*LocationAreaList, error) {
+	return c.GetResourceListContext(ctx, fullURL)
 }
 
 type Pokemon struct {
 	Abilities []struct {
-		Ability struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"ability"`
-		IsHidden bool `json:"is_hidden"`
-		Slot     int  `json:"slot"`
+		Ability  NamedAPIResource `json:"ability"`
+		IsHidden bool             `json:"is_hidden"`
+		Slot     int              `json:"slot"`
 	} `json:"abilities"`
 	BaseExperience int `json:"base_experience"`
 	Cries          struct {
 		Latest string `json:"latest"`
 		Legacy string `json:"legacy"`
 	} `json:"cries"`
-	Forms []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"forms"`
+	Forms       []NamedAPIResource `json:"forms"`
 	GameIndices []struct {
-		GameIndex int `json:"game_index"`
-		Version   struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"version"`
+		GameIndex int              `json:"game_index"`
+		Version   NamedAPIResource `json:"version"`
 	} `json:"game_indices"`
 	Height    int `json:"height"`
 	HeldItems []struct {
-		Item struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"item"`
+		Item           NamedAPIResource `json:"item"`
 		VersionDetails []struct {
-			Rarity  int `json:"rarity"`
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
+			Rarity  int              `json:"rarity"`
+			Version NamedAPIResource `json:"version"`
 		} `json:"version_details"`
 	} `json:"held_items"`
 	ID                     int    `json:"id"`
 	IsDefault              bool   `json:"is_default"`
 	LocationAreaEncounters string `json:"location_area_encounters"`
 	Moves                  []struct {
-		Move struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"move"`
+		Move                NamedAPIResource `json:"move"`
 		VersionGroupDetails []struct {
-			LevelLearnedAt  int `json:"level_learned_at"`
-			MoveLearnMethod struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"move_learn_method"`
-			Order        any `json:"order"`
-			VersionGroup struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version_group"`
+			LevelLearnedAt  int              `json:"level_learned_at"`
+			MoveLearnMethod NamedAPIResource `json:"move_learn_method"`
+			Order           any              `json:"order"`
+			VersionGroup    NamedAPIResource `json:"version_group"`
 		} `json:"version_group_details"`
 	} `json:"moves"`
 	Name          string `json:"name"`
@@ -218,17 +499,11 @@ type Pokemon struct {
 			IsHidden bool `json:"is_hidden"`
 			Slot     int  `json:"slot"`
 		} `json:"abilities"`
-		Generation struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"generation"`
+		Generation NamedAPIResource `json:"generation"`
 	} `json:"past_abilities"`
-	PastTypes []any `json:"past_types"`
-	Species   struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"species"`
-	Sprites struct {
+	PastTypes []any            `json:"past_types"`
+	Species   NamedAPIResource `json:"species"`
+	Sprites   struct {
 		BackDefault      string `json:"back_default"`
 		BackFemale       string `json:"back_female"`
 		BackShiny        string `json:"back_shiny"`
@@ -415,36 +690,24 @@ type Pokemon struct {
 		} `json:"versions"`
 	} `json:"sprites"`
 	Stats []struct {
-		BaseStat int `json:"base_stat"`
-		Effort   int `json:"effort"`
-		Stat     struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"stat"`
+		BaseStat int              `json:"base_stat"`
+		Effort   int              `json:"effort"`
+		Stat     NamedAPIResource `json:"stat"`
 	} `json:"stats"`
 	Types []struct {
-		Slot int `json:"slot"`
-		Type struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"type"`
+		Slot int              `json:"slot"`
+		Type NamedAPIResource `json:"type"`
 	} `json:"types"`
 	Weight int `json:"weight"`
 }
 
 // Get a Pokemon from the provided name
 func (c *Client) GetPokemon(name string) (*Pokemon, error) {
-	fullURL := PokemonURL + name
-	bytes, err := c.FetchBytes(fullURL)
-	if err != nil {
-		return nil, err
-	}
-
-	var pokemon Pokemon
-	err = json.Unmarshal(bytes, &pokemon)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling bytes: %v", err)
-	}
+	return c.GetPokemonContext(context.Background(), name)
+}
 
-	return &pokemon, nil
+// GetPokemon with a context that cancels the request
+func (c *Client) GetPokemonContext(ctx context.Context, name string) (*Pokemon, error) {
+	fullURL := c.endpointURL("pokemon", name)
+	return Get[Pokemon](ctx, c, fullURL)
 }
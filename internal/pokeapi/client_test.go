@@ -1,12 +1,37 @@
 package pokeapi
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokecache"
 )
 
+// Set to record the fixtures in testdata from the live PokeAPI
+const recordEnv = "POKEAPI_RECORD"
+
+// Client that replays responses from testdata, or records them when
+// POKEAPI_RECORD is set
+func newFixtureClient(t *testing.T) *Client {
+	t.Helper()
+	mode := ModeReplay
+	if os.Getenv(recordEnv) != "" {
+		mode = ModeRecord
+	}
+	recorder := NewRecorder("testdata", mode, nil)
+	return NewClient(WithHTTPClient(&http.Client{Transport: recorder}))
+}
+
 func TestFetchBytes(t *testing.T) {
-	client := NewClient()
+	client := newFixtureClient(t)
+	defer client.Close()
 
 	bytes, err := client.FetchBytes("https://www.example.com")
 	if err != nil {
@@ -21,7 +46,8 @@ func TestFetchBytes(t *testing.T) {
 }
 
 func TestGetLocationArea(t *testing.T) {
-	client := NewClient()
+	client := newFixtureClient(t)
+	defer client.Close()
 
 	area, err := client.GetLocationArea("canalave-city-area")
 	if err != nil {
@@ -36,9 +62,10 @@ func TestGetLocationArea(t *testing.T) {
 }
 
 func TestGetLocationAreaList(t *testing.T) {
-	client := NewClient()
+	client := newFixtureClient(t)
+	defer client.Close()
 
-	areas, err := client.GetLocationAreaList(LocationAreaURL)
+	areas, err := client.GetLocationAreaList(client.LocationAreaURL())
 	if err != nil {
 		t.Fatalf("GetLocationAreaList returned error: %v", err)
 	}
@@ -52,3 +79,205 @@ func TestGetLocationAreaList(t *testing.T) {
 		t.Errorf("expected a next page URL")
 	}
 }
+
+func TestNewClientOptions(t *testing.T) {
+	var gotPath, gotAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"name":"pikachu","height":4}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL+"/api/v2"),
+		WithHTTPClient(server.Client()),
+		WithUserAgent("pokedex-test"),
+	)
+	defer client.Close()
+
+	if client.LocationAreaURL() != server.URL+"/api/v2/location-area/" {
+		t.Errorf("unexpected location-area url %s", client.LocationAreaURL())
+	}
+
+	pokemon, err := client.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("GetPokemon returned error: %v", err)
+	}
+	if pokemon.Name != "pikachu" {
+		t.Errorf("expected name 'pikachu', got %s", pokemon.Name)
+	}
+	if gotPath != "/api/v2/pokemon/pikachu" {
+		t.Errorf("expected path '/api/v2/pokemon/pikachu', got %s", gotPath)
+	}
+	if gotAgent != "pokedex-test" {
+		t.Errorf("expected user agent 'pokedex-test', got %s", gotAgent)
+	}
+}
+
+func TestFetchBytesStatusErrors(t *testing.T) {
+	cases := []struct {
+		status   int
+		expected error
+	}{
+		{status: http.StatusNotFound, expected: ErrNotFound},
+		{status: http.StatusTooManyRequests, expected: ErrRateLimited},
+		{status: http.StatusInternalServerError, expected: ErrServer},
+		{status: http.StatusBadGateway, expected: ErrServer},
+	}
+
+	for _, c := range cases {
+		t.Run(http.StatusText(c.status), func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				http.Error(w, http.StatusText(c.status), c.status)
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+			defer client.Close()
+			for range 2 {
+				_, err := client.FetchBytes(server.URL + "/pokemon/missingno")
+				if !errors.Is(err, c.expected) {
+					t.Fatalf("expected %v, got %v", c.expected, err)
+				}
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != c.status {
+					t.Fatalf("expected *StatusError with status %v, got %v", c.status, err)
+				}
+			}
+			if requests != 2 {
+				t.Errorf("expected error responses to skip the cache, got %v requests", requests)
+			}
+		})
+	}
+}
+
+func TestFetchBytesContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := client.GetPokemonContext(ctx, "pikachu")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClientClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	for range 10 {
+		client := NewClient()
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close returned error: %v", err)
+		}
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected %v goroutines after close, got %v", before, after)
+	}
+}
+
+func TestFetchBytesDiskCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("testdata"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for range 2 {
+		// Each client is a new session sharing the disk tier
+		disk, err := pokecache.NewDiskCache(dir, time.Hour, 0)
+		if err != nil {
+			t.Fatalf("NewDiskCache returned error: %v", err)
+		}
+		client := NewClient(WithDiskCache(disk))
+		bytes, err := client.FetchBytes(server.URL)
+		client.Close()
+		if err != nil || string(bytes) != "testdata" {
+			t.Fatalf("expected 'testdata', got %q, %v", bytes, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %v", requests)
+	}
+}
+
+func TestWithStore(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("testdata"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithStore(pokecache.Nop{}))
+	defer client.Close()
+	for range 3 {
+		if _, err := client.FetchBytes(server.URL); err != nil {
+			t.Fatalf("FetchBytes returned error: %v", err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("expected every fetch to miss a no-op store, got %v requests", requests)
+	}
+	if err := client.ClearCache(); err != nil {
+		t.Errorf("ClearCache returned error: %v", err)
+	}
+}
+
+func TestFetchBytesRevalidate(t *testing.T) {
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("testdata"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCacheInterval(50 * time.Millisecond))
+	defer client.Close()
+
+	fetch := func() {
+		t.Helper()
+		bytes, err := client.FetchBytes(server.URL)
+		if err != nil || string(bytes) != "testdata" {
+			t.Fatalf("expected 'testdata', got %q, %v", bytes, err)
+		}
+	}
+
+	fetch()
+	// Let the entry go stale, but not be reaped, so the next fetch revalidates
+	time.Sleep(125 * time.Millisecond)
+	fetch()
+	// The refreshed entry is served from memory without a request
+	fetch()
+	if requests != 2 || notModified != 1 {
+		t.Errorf("expected 2 requests with 1 revalidation, got %v and %v", requests, notModified)
+	}
+}
+
+func TestWithCacheIntervalZero(t *testing.T) {
+	client := NewClient(WithCacheInterval(0))
+	if err := client.Close(); err != nil {
+		t.Errorf("Close returned error: %v", err)
+	}
+}
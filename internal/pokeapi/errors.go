@@ -0,0 +1,57 @@
+package pokeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors matched with errors.Is against a *StatusError
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrRateLimited = errors.New("rate limited")
+	ErrServer      = errors.New("server error")
+	ErrNotInBundle = errors.New("not in offline bundle")
+)
+
+// Returned in offline mode when a bundle file cannot be read or decoded
+var ErrBadBundle = errors.New("bad offline bundle")
+
+// Returned by FetchBytes when PokeAPI responds with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	URL        string
+	RetryAfter time.Duration // parsed Retry-After header, 0 when absent
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("error get url %v: %v %v", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Unwraps to the sentinel matching the status code, if any
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrServer
+	}
+	return nil
+}
+
+// Returned in offline mode when the bundle has no response for a URL
+type NotInBundleError struct {
+	URL  string
+	Path string // bundle file that was looked up
+}
+
+func (e *NotInBundleError) Error() string {
+	return fmt.Sprintf("%v is not in the offline bundle (no %v)", e.URL, e.Path)
+}
+
+func (e *NotInBundleError) Unwrap() error {
+	return ErrNotInBundle
+}
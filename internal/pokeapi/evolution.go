@@ -0,0 +1,160 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type EvolutionChain struct {
+	BabyTriggerItem *NamedAPIResource `json:"baby_trigger_item"`
+	Chain           ChainLink         `json:"chain"`
+	ID              int               `json:"id"`
+}
+
+// One species in an evolution chain and the species it evolves into
+type ChainLink struct {
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+	IsBaby           bool              `json:"is_baby"`
+	Species          NamedAPIResource  `json:"species"`
+}
+
+// Conditions for evolving into a ChainLink, unset conditions are nil or empty
+type EvolutionDetail struct {
+	Gender                *int              `json:"gender"`
+	HeldItem              *NamedAPIResource `json:"held_item"`
+	Item                  *NamedAPIResource `json:"item"`
+	KnownMove             *NamedAPIResource `json:"known_move"`
+	KnownMoveType         *NamedAPIResource `json:"known_move_type"`
+	Location              *NamedAPIResource `json:"location"`
+	MinAffection          *int              `json:"min_affection"`
+	MinBeauty             *int              `json:"min_beauty"`
+	MinHappiness          *int              `json:"min_happiness"`
+	MinLevel              *int              `json:"min_level"`
+	NeedsOverworldRain    bool              `json:"needs_overworld_rain"`
+	PartySpecies          *NamedAPIResource `json:"party_species"`
+	PartyType             *NamedAPIResource `json:"party_type"`
+	RelativePhysicalStats *int              `json:"relative_physical_stats"`
+	TimeOfDay             string            `json:"time_of_day"`
+	TradeSpecies          *NamedAPIResource `json:"trade_species"`
+	Trigger               NamedAPIResource  `json:"trigger"`
+	TurnUpsideDown        bool              `json:"turn_upside_down"`
+}
+
+// Get an evolution chain from its id
+func (c *Client) GetEvolutionChain(id int) (*EvolutionChain, error) {
+	return c.GetEvolutionChainContext(context.Background(), id)
+}
+
+// GetEvolutionChain with a context that cancels the request
+func (c *Client) GetEvolutionChainContext(ctx context.Context, id int) (*EvolutionChain, error) {
+	return Get[EvolutionChain](ctx, c, c.endpointURL("evolution-chain", strconv.Itoa(id)))
+}
+
+// Get the evolution chain a species belongs to. Decoded chains are kept until
+// ClearCache and shared by every species in the chain.
+func (c *Client) GetSpeciesEvolutionChain(species string) (*EvolutionChain, error) {
+	return c.GetSpeciesEvolutionChainContext(context.Background(), species)
+}
+
+// GetSpeciesEvolutionChain with a context that cancels the requests
+func (c *Client) GetSpeciesEvolutionChainContext(ctx context.Context, species string) (*EvolutionChain, error) {
+	c.evolutionsMu.Lock()
+	chain, ok := c.evolutions[species]
+	c.evolutionsMu.Unlock()
+	if ok {
+		return chain, nil
+	}
+
+	s, err := c.GetPokemonSpeciesContext(ctx, species)
+	if err != nil {
+		return nil, err
+	}
+	if s.EvolutionChain.URL == "" {
+		return nil, fmt.Errorf("species %v has no evolution chain", species)
+	}
+	chain, err = Resolve[EvolutionChain](ctx, c, s.EvolutionChain)
+	if err != nil {
+		return nil, err
+	}
+
+	c.evolutionsMu.Lock()
+	defer c.evolutionsMu.Unlock()
+	chain.Chain.Walk(func(link ChainLink, depth int) {
+		c.evolutions[link.Species.Name] = chain
+	})
+	c.evolutions[species] = chain
+	return chain, nil
+}
+
+// Calls fn for the link and every link it evolves into, depth first. The
+// root link has depth 0.
+func (l ChainLink) Walk(fn func(link ChainLink, depth int)) {
+	l.walk(fn, 0)
+}
+
+func (l ChainLink) walk(fn func(link ChainLink, depth int), depth int) {
+	fn(l, depth)
+	for _, next := range l.EvolvesTo {
+		next.walk(fn, depth+1)
+	}
+}
+
+// Human readable conditions, e.g. "level-up, level 16" or "use-item, thunder-stone"
+func (d EvolutionDetail) String() string {
+	parts := []string{d.Trigger.Name}
+	if d.Item != nil {
+		parts = append(parts, d.Item.Name)
+	}
+	if d.MinLevel != nil {
+		parts = append(parts, fmt.Sprintf("level %v", *d.MinLevel))
+	}
+	if d.HeldItem != nil {
+		parts = append(parts, "holding "+d.HeldItem.Name)
+	}
+	if d.MinHappiness != nil {
+		parts = append(parts, fmt.Sprintf("happiness %v", *d.MinHappiness))
+	}
+	if d.MinAffection != nil {
+		parts = append(parts, fmt.Sprintf("affection %v", *d.MinAffection))
+	}
+	if d.MinBeauty != nil {
+		parts = append(parts, fmt.Sprintf("beauty %v", *d.MinBeauty))
+	}
+	if d.KnownMove != nil {
+		parts = append(parts, "knowing "+d.KnownMove.Name)
+	}
+	if d.KnownMoveType != nil {
+		parts = append(parts, "knowing a "+d.KnownMoveType.Name+" move")
+	}
+	if d.Location != nil {
+		parts = append(parts, "at "+d.Location.Name)
+	}
+	if d.TimeOfDay != "" {
+		parts = append(parts, "during the "+d.TimeOfDay)
+	}
+	if d.PartySpecies != nil {
+		parts = append(parts, "with "+d.PartySpecies.Name+" in party")
+	}
+	if d.PartyType != nil {
+		parts = append(parts, "with a "+d.PartyType.Name+" type in party")
+	}
+	if d.TradeSpecies != nil {
+		parts = append(parts, "for "+d.TradeSpecies.Name)
+	}
+	if d.Gender != nil {
+		parts = append(parts, map[int]string{1: "female", 2: "male"}[*d.Gender])
+	}
+	if d.RelativePhysicalStats != nil {
+		parts = append(parts, map[int]string{-1: "attack < defense", 0: "attack = defense", 1: "attack > defense"}[*d.RelativePhysicalStats])
+	}
+	if d.NeedsOverworldRain {
+		parts = append(parts, "while raining")
+	}
+	if d.TurnUpsideDown {
+		parts = append(parts, "upside down")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,117 @@
+package pokeapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const eeveeChainJSON = `{
+	"id": 67,
+	"chain": {
+		"species": {"name": "eevee", "url": ""},
+		"evolution_details": [],
+		"evolves_to": [
+			{
+				"species": {"name": "vaporeon", "url": ""},
+				"evolution_details": [{"trigger": {"name": "use-item", "url": ""}, "item": {"name": "water-stone", "url": ""}}],
+				"evolves_to": []
+			},
+			{
+				"species": {"name": "espeon", "url": ""},
+				"evolution_details": [{"trigger": {"name": "level-up", "url": ""}, "min_happiness": 160, "time_of_day": "day"}],
+				"evolves_to": []
+			}
+		]
+	}
+}`
+
+func TestGetSpeciesEvolutionChain(t *testing.T) {
+	requests := map[string]int{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/pokemon-species/eevee":
+			w.Write([]byte(`{"name":"eevee","evolution_chain":{"url":"` + server.URL + `/evolution-chain/67/"}}`))
+		case "/evolution-chain/67/":
+			w.Write([]byte(eeveeChainJSON))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+
+	chain, err := client.GetSpeciesEvolutionChain("eevee")
+	if err != nil {
+		t.Fatalf("GetSpeciesEvolutionChain returned error: %v", err)
+	}
+	if chain.ID != 67 || len(chain.Chain.EvolvesTo) != 2 {
+		t.Fatalf("unexpected chain %+v", chain)
+	}
+
+	// Every species in the chain is served from the decoded chain
+	again, err := client.GetSpeciesEvolutionChain("espeon")
+	if err != nil {
+		t.Fatalf("GetSpeciesEvolutionChain returned error: %v", err)
+	}
+	if again != chain {
+		t.Errorf("expected espeon to share the cached chain")
+	}
+	if requests["/pokemon-species/espeon"] != 0 || requests["/evolution-chain/67/"] != 1 {
+		t.Errorf("expected one chain request, got %v", requests)
+	}
+
+	var walked []string
+	chain.Chain.Walk(func(link ChainLink, depth int) {
+		walked = append(walked, link.Species.Name)
+	})
+	if len(walked) != 3 || walked[0] != "eevee" || walked[2] != "espeon" {
+		t.Errorf("unexpected walk order %v", walked)
+	}
+
+	expected := []string{"use-item, water-stone", "level-up, happiness 160, during the day"}
+	for i, next := range chain.Chain.EvolvesTo {
+		if actual := next.EvolutionDetails[0].String(); actual != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], actual)
+		}
+	}
+}
+
+func TestClearCacheEvolutionChains(t *testing.T) {
+	requests := map[string]int{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/pokemon-species/eevee":
+			w.Write([]byte(`{"name":"eevee","evolution_chain":{"url":"` + server.URL + `/evolution-chain/67/"}}`))
+		case "/evolution-chain/67/":
+			w.Write([]byte(eeveeChainJSON))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+
+	first, err := client.GetSpeciesEvolutionChain("eevee")
+	if err != nil {
+		t.Fatalf("GetSpeciesEvolutionChain returned error: %v", err)
+	}
+	if err := client.ClearCache(); err != nil {
+		t.Fatalf("ClearCache returned error: %v", err)
+	}
+	again, err := client.GetSpeciesEvolutionChain("eevee")
+	if err != nil {
+		t.Fatalf("GetSpeciesEvolutionChain returned error: %v", err)
+	}
+	if again == first || requests["/evolution-chain/67/"] != 2 {
+		t.Errorf("expected the chain to be fetched again after ClearCache, got %v", requests)
+	}
+}
@@ -0,0 +1,176 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokecache"
+)
+
+// Store counting writes, to check coalesced callers share one cache write
+type countingStore struct {
+	pokecache.Nop
+	adds atomic.Int32
+}
+
+func (s *countingStore) Add(key string, val []byte) {
+	s.adds.Add(1)
+}
+
+func TestFetchBytesCoalesces(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte("testdata"))
+	}))
+	defer server.Close()
+
+	store := &countingStore{}
+	client := NewClient(WithStore(store))
+	defer client.Close()
+
+	const callers = 10
+	var wg sync.WaitGroup
+	bodies := make([][]byte, callers)
+	errs := make([]error, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bodies[i], errs[i] = client.FetchBytes(server.URL)
+		}()
+	}
+
+	// Let every caller join the in-flight request before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range callers {
+		if errs[i] != nil || string(bodies[i]) != "testdata" {
+			t.Errorf("caller %v: expected 'testdata', got %q, %v", i, bodies[i], errs[i])
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %v", requests.Load())
+	}
+	if store.adds.Load() != 1 {
+		t.Errorf("expected 1 cache write, got %v", store.adds.Load())
+	}
+
+	// Callers get their own copy of the body
+	bodies[0][0] = 'X'
+	if string(bodies[1]) != "testdata" {
+		t.Errorf("expected bodies to be independent, got %q", bodies[1])
+	}
+}
+
+func TestFetchBytesCoalescedCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte("testdata"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithStore(pokecache.Nop{}))
+	defer client.Close()
+
+	// One caller giving up does not cancel the request for the other
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelledErr := make(chan error, 1)
+	go func() {
+		_, err := client.FetchBytesContext(ctx, server.URL)
+		cancelledErr <- err
+	}()
+	body := make(chan []byte, 1)
+	go func() {
+		b, _ := client.FetchBytes(server.URL)
+		body <- b
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-cancelledErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	close(release)
+	if b := <-body; string(b) != "testdata" {
+		t.Errorf("expected 'testdata' for the remaining caller, got %q", b)
+	}
+
+	// Once every caller gives up the request is cancelled
+	serverCancelled := make(chan struct{})
+	blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(serverCancelled)
+	}))
+	defer blocked.Close()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.FetchBytesContext(ctx, blocked.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	select {
+	case <-serverCancelled:
+	case <-time.After(time.Second):
+		t.Errorf("expected the abandoned request to be cancelled")
+	}
+}
+
+// Transport built from a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestFetchBytesAfterAbandonedFlight(t *testing.T) {
+	// The first request ignores cancellation until released, so the
+	// abandoned call is still running when the next caller arrives
+	release := make(chan struct{})
+	var calls atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 1 {
+			<-release
+			return nil, req.Context().Err()
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("testdata")),
+			Request:    req,
+		}, nil
+	})
+	client := NewClient(
+		WithStore(pokecache.Nop{}),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+	)
+	defer client.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.FetchBytesContext(ctx, "http://pokeapi.test/pokemon/pikachu"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	// Joining the abandoned call would block until release and time out
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	b, err := client.FetchBytesContext(ctx, "http://pokeapi.test/pokemon/pikachu")
+	if err != nil || string(b) != "testdata" {
+		t.Errorf("expected a fresh request to succeed, got %q, %v", b, err)
+	}
+}
@@ -0,0 +1,89 @@
+package pokeapi
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// One page of any PokeAPI list endpoint
+type NamedAPIResourceList struct {
+	Count    int                `json:"count"`
+	Next     *string            `json:"next"`
+	Previous *string            `json:"previous"`
+	Results  []NamedAPIResource `json:"results"`
+}
+
+// Paging of a list endpoint, zero values use PokeAPI's defaults (20 from the start)
+type ListOptions struct {
+	Limit  int
+	Offset int
+}
+
+// URL of the first page of a resource list, e.g. ListURL("pokemon", ListOptions{Limit: 100})
+func (c *Client) ListURL(resource string, opts ListOptions) string {
+	listURL := c.endpointURL(resource, "")
+	query := url.Values{}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if len(query) > 0 {
+		listURL += "?" + query.Encode()
+	}
+	return listURL
+}
+
+// Get a page of any resource list from a url, such as a page's Next link
+func (c *Client) GetResourceList(fullURL string) (*NamedAPIResourceList, error) {
+	return c.GetResourceListContext(context.Background(), fullURL)
+}
+
+// GetResourceList with a context that cancels the request
+func (c *Client) GetResourceListContext(ctx context.Context, fullURL string) (*NamedAPIResourceList, error) {
+	return Get[NamedAPIResourceList](ctx, c, fullURL)
+}
+
+// Iterate over the pages of a resource list, following Next links until the
+// last page. Iteration stops after the first error.
+func (c *Client) Pages(ctx context.Context, resource string, opts ListOptions) iter.Seq2[*NamedAPIResourceList, error] {
+	return func(yield func(*NamedAPIResourceList, error) bool) {
+		next := c.ListURL(resource, opts)
+		for next != "" {
+			page, err := c.GetResourceListContext(ctx, next)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+
+			next = ""
+			if page.Next != nil {
+				next = *page.Next
+			}
+		}
+	}
+}
+
+// Iterate over every resource of a list, e.g. all pokemon, types, moves,
+// items or berries. Pages are fetched as needed with opts.Limit per page.
+func (c *Client) List(ctx context.Context, resource string, opts ListOptions) iter.Seq2[NamedAPIResource, error] {
+	return func(yield func(NamedAPIResource, error) bool) {
+		for page, err := range c.Pages(ctx, resource, opts) {
+			if err != nil {
+				yield(NamedAPIResource{}, err)
+				return
+			}
+			for _, result := range page.Results {
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
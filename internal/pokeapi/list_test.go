@@ -0,0 +1,128 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// Serves /pokemon/ as a paginated list of names, like PokeAPI
+func newListServer(t *testing.T, names []string, requests *int) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.URL.Path != "/pokemon/" {
+			http.NotFound(w, r)
+			return
+		}
+		limit, offset := 20, 0
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
+			limit = v
+		}
+		if v, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil {
+			offset = v
+		}
+
+		page := NamedAPIResourceList{Count: len(names)}
+		for i := offset; i < len(names) && i < offset+limit; i++ {
+			page.Results = append(page.Results, NamedAPIResource{Name: names[i], URL: fmt.Sprintf("%v/pokemon/%v/", server.URL, i+1)})
+		}
+		if offset+limit < len(names) {
+			next := fmt.Sprintf("%v/pokemon/?offset=%v&limit=%v", server.URL, offset+limit, limit)
+			page.Next = &next
+		}
+		if offset > 0 {
+			previous := fmt.Sprintf("%v/pokemon/?offset=%v&limit=%v", server.URL, max(offset-limit, 0), limit)
+			page.Previous = &previous
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	return server
+}
+
+func TestList(t *testing.T) {
+	names := []string{"bulbasaur", "ivysaur", "venusaur", "charmander", "charmeleon"}
+	requests := 0
+	server := newListServer(t, names, &requests)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+
+	var listed []string
+	for resource, err := range client.List(context.Background(), "pokemon", ListOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		listed = append(listed, resource.Name)
+	}
+	if fmt.Sprint(listed) != fmt.Sprint(names) {
+		t.Errorf("expected %v, got %v", names, listed)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 page requests, got %v", requests)
+	}
+
+	// Breaking early stops paging
+	requests = 0
+	for range client.List(context.Background(), "pokemon", ListOptions{Limit: 1, Offset: 3}) {
+		break
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 page request after break, got %v", requests)
+	}
+}
+
+func TestPages(t *testing.T) {
+	requests := 0
+	server := newListServer(t, []string{"a", "b", "c"}, &requests)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+
+	sizes := []int{}
+	for page, err := range client.Pages(context.Background(), "pokemon", ListOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("Pages returned error: %v", err)
+		}
+		if page.Count != 3 {
+			t.Errorf("expected count 3, got %v", page.Count)
+		}
+		sizes = append(sizes, len(page.Results))
+	}
+	if fmt.Sprint(sizes) != "[2 1]" {
+		t.Errorf("expected page sizes [2 1], got %v", sizes)
+	}
+
+	for _, err := range client.Pages(context.Background(), "berry", ListOptions{}) {
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	}
+}
+
+func TestListURL(t *testing.T) {
+	client := NewClient(WithBaseURL("https://example.com/api/v2"))
+	defer client.Close()
+
+	cases := []struct {
+		opts     ListOptions
+		expected string
+	}{
+		{opts: ListOptions{}, expected: "https://example.com/api/v2/type/"},
+		{opts: ListOptions{Limit: 50}, expected: "https://example.com/api/v2/type/?limit=50"},
+		{opts: ListOptions{Limit: 50, Offset: 100}, expected: "https://example.com/api/v2/type/?limit=50&offset=100"},
+	}
+	for _, c := range cases {
+		if actual := client.ListURL("type", c.opts); actual != c.expected {
+			t.Errorf("ListURL(%+v) = %v, expected %v", c.opts, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package pokeapi
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+type Move struct {
+	Accuracy      *int             `json:"accuracy"`
+	DamageClass   NamedAPIResource `json:"damage_class"`
+	EffectChance  *int             `json:"effect_chance"`
+	EffectEntries []struct {
+		Effect      string           `json:"effect"`
+		Language    NamedAPIResource `json:"language"`
+		ShortEffect string           `json:"short_effect"`
+	} `json:"effect_entries"`
+	Generation NamedAPIResource `json:"generation"`
+	ID         int              `json:"id"`
+	Name       string           `json:"name"`
+	Power      *int             `json:"power"`
+	PP         *int             `json:"pp"`
+	Priority   int              `json:"priority"`
+	Target     NamedAPIResource `json:"target"`
+	Type       NamedAPIResource `json:"type"`
+}
+
+// Get a move from the provided name
+func (c *Client) GetMove(name string) (*Move, error) {
+	return c.GetMoveContext(context.Background(), name)
+}
+
+// GetMove with a context that cancels the request
+func (c *Client) GetMoveContext(ctx context.Context, name string) (*Move, error) {
+	fullURL := c.endpointURL("move", name)
+	return Get[Move](ctx, c, fullURL)
+}
+
+// Short effect text in the given language, falling back to DefaultLanguage.
+// The $effect_chance placeholder is replaced with the move's chance.
+func (m *Move) ShortEffect(language string) string {
+	for _, lang := range fallbackLanguages(language) {
+		for _, entry := range m.EffectEntries {
+			if entry.Language.Name != lang {
+				continue
+			}
+			effect := strings.Join(strings.Fields(entry.ShortEffect), " ")
+			if m.EffectChance != nil {
+				effect = strings.ReplaceAll(effect, "$effect_chance", strconv.Itoa(*m.EffectChance))
+			}
+			return effect
+		}
+	}
+	return ""
+}
+
+// One way a Pokemon learns a move in a version group
+type LearnsetEntry struct {
+	Move         string
+	Method       string // level-up, machine, egg, tutor, ...
+	Level        int    // 0 unless learned by level-up
+	VersionGroup string
+}
+
+// Moves the Pokemon learns, filtered by versionGroup and method when they are
+// not empty. An empty versionGroup selects the newest group the Pokemon has moves in.
+func (p *Pokemon) Learnset(versionGroup, method string) []LearnsetEntry {
+	if versionGroup == "" {
+		versionGroup = p.LatestVersionGroup()
+	}
+
+	var learnset []LearnsetEntry
+	for _, move := range p.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.VersionGroup.Name != versionGroup {
+				continue
+			}
+			if method != "" && detail.MoveLearnMethod.Name != method {
+				continue
+			}
+			learnset = append(learnset, LearnsetEntry{
+				Move:         move.Move.Name,
+				Method:       detail.MoveLearnMethod.Name,
+				Level:        detail.LevelLearnedAt,
+				VersionGroup: detail.VersionGroup.Name,
+			})
+		}
+	}
+	return learnset
+}
+
+// Newest version group the Pokemon has moves in, judged by the id in its URL
+func (p *Pokemon) LatestVersionGroup() string {
+	latest, latestID := "", -1
+	for _, move := range p.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			id := resourceID(detail.VersionGroup.URL)
+			if id > latestID {
+				latest, latestID = detail.VersionGroup.Name, id
+			}
+		}
+	}
+	return latest
+}
+
+// Id at the end of a resource URL like .../version-group/25/, 0 when absent
+func resourceID(resourceURL string) int {
+	parts := strings.Split(strings.TrimRight(resourceURL, "/"), "/")
+	id, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,82 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const bulbasaurMovesJSON = `{"name":"bulbasaur","moves":[
+	{"move":{"name":"tackle"},"version_group_details":[
+		{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"red-blue","url":"https://pokeapi.co/api/v2/version-group/1/"}},
+		{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}
+	]},
+	{"move":{"name":"vine-whip"},"version_group_details":[
+		{"level_learned_at":3,"move_learn_method":{"name":"level-up"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}
+	]},
+	{"move":{"name":"swords-dance"},"version_group_details":[
+		{"level_learned_at":0,"move_learn_method":{"name":"machine"},"version_group":{"name":"red-blue","url":"https://pokeapi.co/api/v2/version-group/1/"}}
+	]}
+]}`
+
+func TestLearnset(t *testing.T) {
+	var pokemon Pokemon
+	if err := json.Unmarshal([]byte(bulbasaurMovesJSON), &pokemon); err != nil {
+		t.Fatalf("error unmarshalling fixture: %v", err)
+	}
+
+	if latest := pokemon.LatestVersionGroup(); latest != "scarlet-violet" {
+		t.Errorf("expected latest version group scarlet-violet, got %v", latest)
+	}
+
+	cases := []struct {
+		versionGroup string
+		method       string
+		expected     []string
+	}{
+		{versionGroup: "", method: "", expected: []string{"tackle", "vine-whip"}},
+		{versionGroup: "red-blue", method: "", expected: []string{"tackle", "swords-dance"}},
+		{versionGroup: "red-blue", method: "machine", expected: []string{"swords-dance"}},
+		{versionGroup: "scarlet-violet", method: "egg", expected: nil},
+	}
+	for _, c := range cases {
+		learnset := pokemon.Learnset(c.versionGroup, c.method)
+		if len(learnset) != len(c.expected) {
+			t.Errorf("Learnset(%q, %q) = %+v, expected %v", c.versionGroup, c.method, learnset, c.expected)
+			continue
+		}
+		for i := range learnset {
+			if learnset[i].Move != c.expected[i] {
+				t.Errorf("Learnset(%q, %q)[%v] = %v, expected %v", c.versionGroup, c.method, i, learnset[i].Move, c.expected[i])
+			}
+		}
+	}
+}
+
+func TestGetMove(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/move/thunderbolt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name":"thunderbolt","power":90,"accuracy":100,"pp":15,"effect_chance":10,
+			"type":{"name":"electric"},"damage_class":{"name":"special"},
+			"effect_entries":[{"short_effect":"Has a $effect_chance% chance to paralyze the target.","language":{"name":"en"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+
+	move, err := client.GetMove("thunderbolt")
+	if err != nil {
+		t.Fatalf("GetMove returned error: %v", err)
+	}
+	if *move.Power != 90 || *move.PP != 15 || move.DamageClass.Name != "special" {
+		t.Errorf("unexpected move %+v", move)
+	}
+	if effect := move.ShortEffect(""); effect != "Has a 10% chance to paralyze the target." {
+		t.Errorf("unexpected effect %q", effect)
+	}
+}
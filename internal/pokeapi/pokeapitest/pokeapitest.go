@@ -0,0 +1,273 @@
+// Package pokeapitest runs an in-process fake PokeAPI for tests.
+package pokeapitest
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokeapi"
+)
+
+// Path every endpoint is served under, as on pokeapi.co
+const apiPath = "/api/v2/"
+
+// Base URL of resource links in the seed data, rewritten to the server's URL
+const seedBaseURL = "https://pokeapi.co/api/v2/"
+
+// Default page size of list endpoints, as on pokeapi.co
+const defaultLimit = 20
+
+//go:embed seed/*.json
+var seedFS embed.FS
+
+// A fake PokeAPI serving resources by name or ID, paginated resource lists,
+// and on request 429s and slow responses. Unknown resources are 404s.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	resources  map[string]map[int]resource // by resource, then ID
+	names      map[string]map[string]int   // IDs by resource, then name
+	rateLimits int                         // requests left to answer with 429
+	retryAfter time.Duration
+	delay      time.Duration
+	requests   []string // paths requested, in order
+}
+
+type resource struct {
+	name string
+	body []byte
+}
+
+// Start a server seeded with a few Pokemon and the first 40 Sinnoh location
+// areas. The server is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{
+		resources: make(map[string]map[int]resource),
+		names:     make(map[string]map[string]int),
+	}
+	for _, name := range []string{"pokemon", "location-area"} {
+		data, err := seedFS.ReadFile("seed/" + name + ".json")
+		if err != nil {
+			t.Fatalf("error reading %v seed: %v", name, err)
+		}
+		var bodies []json.RawMessage
+		if err := json.Unmarshal(data, &bodies); err != nil {
+			t.Fatalf("error decoding %v seed: %v", name, err)
+		}
+		for _, body := range bodies {
+			if err := s.AddJSON(name, body); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Base URL of the fake API, for pokeapi.WithBaseURL
+func (s *Server) BaseURL() string {
+	return s.URL + apiPath
+}
+
+// A client of the fake API without rate limiting and with short retry delays,
+// closed when the test ends. opts are applied last.
+func (s *Server) Client(t testing.TB, opts ...pokeapi.Option) *pokeapi.Client {
+	t.Helper()
+	defaults := []pokeapi.Option{
+		pokeapi.WithBaseURL(s.BaseURL()),
+		pokeapi.WithRateLimit(0, 0),
+		pokeapi.WithRetryPolicy(pokeapi.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    10 * time.Millisecond,
+		}),
+	}
+	client := pokeapi.NewClient(append(defaults, opts...)...)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// Add a resource such as a pokeapi.Pokemon, replacing any with the same ID.
+// v must encode to JSON with an "id" and a "name".
+func (s *Server) Add(resourceName string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error encoding %v: %v", resourceName, err)
+	}
+	return s.AddJSON(resourceName, body)
+}
+
+// Add a resource from its JSON body, which must have an "id" and a "name"
+func (s *Server) AddJSON(resourceName string, body []byte) error {
+	var key struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &key); err != nil {
+		return fmt.Errorf("error decoding %v: %v", resourceName, err)
+	}
+	if key.ID == 0 || key.Name == "" {
+		return fmt.Errorf("error adding %v: missing id or name", resourceName)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resources[resourceName] == nil {
+		s.resources[resourceName] = make(map[int]resource)
+		s.names[resourceName] = make(map[string]int)
+	}
+	if old, ok := s.resources[resourceName][key.ID]; ok {
+		delete(s.names[resourceName], old.name)
+	}
+	s.resources[resourceName][key.ID] = resource{name: key.Name, body: body}
+	s.names[resourceName][key.Name] = key.ID
+	return nil
+}
+
+// Answer the next n requests with 429 Too Many Requests and a Retry-After of
+// retryAfter, rounded up to whole seconds
+func (s *Server) RateLimit(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimits = n
+	s.retryAfter = retryAfter
+}
+
+// Wait delay before answering each request, or until the request is cancelled
+func (s *Server) SetDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = delay
+}
+
+// Paths of every request received so far, including query strings
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	delay := s.delay
+	limited := s.rateLimits > 0
+	if limited {
+		s.rateLimits--
+	}
+	retryAfter := s.retryAfter
+	s.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if limited {
+		seconds := int((retryAfter + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, apiPath)
+	if !ok || r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+	resourceName, key, _ := strings.Cut(strings.Trim(path, "/"), "/")
+
+	var body []byte
+	if key == "" {
+		body, ok = s.list(r, resourceName)
+	} else {
+		body, ok = s.get(resourceName, key)
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(bytes.ReplaceAll(body, []byte(seedBaseURL), []byte(s.BaseURL())))
+}
+
+// Body of a resource by name or ID
+func (s *Server) get(resourceName, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, err := strconv.Atoi(key)
+	if err != nil {
+		id = s.names[resourceName][key]
+	}
+	res, ok := s.resources[resourceName][id]
+	return res.body, ok
+}
+
+// Page of a resource list in ID order, paged like PokeAPI with limit and offset
+func (s *Server) list(r *http.Request, resourceName string) ([]byte, bool) {
+	s.mu.Lock()
+	resources, ok := s.resources[resourceName]
+	ids := make([]int, 0, len(resources))
+	for id := range resources {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = resources[id].name
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	listURL := s.BaseURL() + resourceName + "/"
+	page := pokeapi.NamedAPIResourceList{
+		Count:   len(ids),
+		Results: []pokeapi.NamedAPIResource{},
+	}
+	for i := offset; i < len(ids) && i < offset+limit; i++ {
+		page.Results = append(page.Results, pokeapi.NamedAPIResource{
+			Name: names[i],
+			URL:  fmt.Sprintf("%v%v/", listURL, ids[i]),
+		})
+	}
+	if offset+limit < len(ids) {
+		next := fmt.Sprintf("%v?offset=%v&limit=%v", listURL, offset+limit, limit)
+		page.Next = &next
+	}
+	if offset > 0 {
+		previous := fmt.Sprintf("%v?offset=%v&limit=%v", listURL, max(offset-limit, 0), limit)
+		page.Previous = &previous
+	}
+
+	body, err := json.Marshal(page)
+	return body, err == nil
+}
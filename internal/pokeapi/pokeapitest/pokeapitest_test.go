@@ -0,0 +1,131 @@
+package pokeapitest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokeapi"
+)
+
+func TestServerResources(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client(t)
+
+	cases := []struct {
+		name   string
+		expect string
+	}{
+		{name: "pikachu", expect: "pikachu"},
+		{name: "120", expect: "staryu"},
+	}
+	for _, c := range cases {
+		pokemon, err := client.GetPokemon(c.name)
+		if err != nil || pokemon.Name != c.expect {
+			t.Errorf("expected %v for %v, got %+v, %v", c.expect, c.name, pokemon, err)
+		}
+	}
+
+	area, err := client.GetLocationArea("canalave-city-area")
+	if err != nil {
+		t.Fatalf("GetLocationArea returned error: %v", err)
+	}
+	if len(area.PokemonEncounters) == 0 {
+		t.Fatalf("expected encounters in canalave-city-area")
+	}
+	// Links point back at the fake server
+	if _, err := pokeapi.Resolve[pokeapi.Pokemon](context.Background(), client, area.PokemonEncounters[0].Pokemon); err != nil {
+		t.Errorf("expected encounter link to resolve, got %v", err)
+	}
+
+	if _, err := client.GetPokemon("missingno"); !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestServerAdd(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client(t)
+
+	if err := server.Add("pokemon", pokeapi.Pokemon{ID: 151, Name: "mew", BaseExperience: 300}); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+	pokemon, err := client.GetPokemon("mew")
+	if err != nil || pokemon.BaseExperience != 300 {
+		t.Errorf("expected added mew, got %+v, %v", pokemon, err)
+	}
+
+	if err := server.Add("pokemon", map[string]any{"name": "no-id"}); err == nil {
+		t.Errorf("expected error adding a resource without an id")
+	}
+}
+
+func TestServerPagination(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client(t)
+
+	page, err := client.GetLocationAreaList(client.LocationAreaURL())
+	if err != nil {
+		t.Fatalf("GetLocationAreaList returned error: %v", err)
+	}
+	if page.Count != 40 || len(page.Results) != 20 || page.Previous != nil || page.Next == nil {
+		t.Fatalf("expected first page of 20 of 40, got %+v", page)
+	}
+	if page.Results[0].Name != "canalave-city-area" {
+		t.Errorf("expected canalave-city-area first, got %v", page.Results[0].Name)
+	}
+
+	page, err = client.GetLocationAreaList(*page.Next)
+	if err != nil {
+		t.Fatalf("GetLocationAreaList returned error: %v", err)
+	}
+	if page.Results[0].Name != "mt-coronet-1f-route-216" || page.Next != nil || page.Previous == nil {
+		t.Errorf("expected last page starting at mt-coronet-1f-route-216, got %+v", page)
+	}
+
+	n := 0
+	for _, err := range client.List(context.Background(), "location-area", pokeapi.ListOptions{Limit: 15}) {
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		n++
+	}
+	if n != 40 {
+		t.Errorf("expected 40 areas, got %v", n)
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client(t)
+
+	// Retries within the client's short delays get through
+	server.RateLimit(1, 0)
+	if _, err := client.GetPokemon("pikachu"); err != nil {
+		t.Errorf("expected retry after 429 to succeed, got %v", err)
+	}
+
+	// A Retry-After longer than the client will wait is returned as an error
+	server.RateLimit(5, time.Minute)
+	_, err := client.GetPokemon("staryu")
+	var statusErr *pokeapi.StatusError
+	if !errors.Is(err, pokeapi.ErrRateLimited) || !errors.As(err, &statusErr) || statusErr.RetryAfter != time.Minute {
+		t.Errorf("expected ErrRateLimited with Retry-After, got %v", err)
+	}
+	if requests := server.Requests(); len(requests) != 3 {
+		t.Errorf("expected 3 requests, got %v", requests)
+	}
+}
+
+func TestServerDelay(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client(t)
+	server.SetDelay(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetPokemonContext(ctx, "pikachu"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected slow response to time out, got %v", err)
+	}
+}
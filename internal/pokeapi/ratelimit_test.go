@@ -0,0 +1,104 @@
+package pokeapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(10, 2)
+	limiter.last = now
+	limiter.now = func() time.Time { return now }
+
+	cases := []struct {
+		advance  time.Duration
+		expected time.Duration
+	}{
+		{advance: 0, expected: 0},                      // burst token 1
+		{advance: 0, expected: 0},                      // burst token 2
+		{advance: 0, expected: 100 * time.Millisecond}, // bucket empty
+		{advance: 0, expected: 200 * time.Millisecond}, // queued behind the previous wait
+		{advance: time.Second, expected: 0},            // refilled to burst
+	}
+
+	for i, c := range cases {
+		now = now.Add(c.advance)
+		if actual := limiter.reserve(); actual != c.expected {
+			t.Errorf("reserve %v: expected %v, got %v", i, c.expected, actual)
+		}
+	}
+}
+
+func TestRateLimiterWaitCancel(t *testing.T) {
+	limiter := NewRateLimiter(0.001, 1)
+	limiter.reserve()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := limiter.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	var debug bytes.Buffer
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(100, 1),
+		WithDebugLogger(log.New(&debug, "", 0)),
+	)
+	defer client.Close()
+
+	// Cache hits must not use up tokens
+	for range 5 {
+		if _, err := client.FetchBytes(server.URL + "/cached"); err != nil {
+			t.Fatalf("FetchBytes returned error: %v", err)
+		}
+	}
+
+	const fetches = 5
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range fetches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.FetchBytes(server.URL + "/" + string(rune('a'+i))); err != nil {
+				t.Errorf("FetchBytes returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// One token left from the cached fetch refilling, then 10ms per request
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected %v fetches at 100 rps to take at least 30ms, took %v", fetches, elapsed)
+	}
+	if requests != fetches+1 {
+		t.Errorf("expected %v requests, got %v", fetches+1, requests)
+	}
+	if !strings.Contains(debug.String(), "rate limiter waited") {
+		t.Errorf("expected wait in debug output, got %q", debug.String())
+	}
+}
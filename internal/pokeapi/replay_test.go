@@ -0,0 +1,85 @@
+package pokeapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"pikachu"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for _, mode := range []RecordMode{ModeRecord, ModeReplay} {
+		recorder := NewRecorder(dir, mode, nil)
+		client := NewClient(WithBaseURL(server.URL), WithHTTPClient(&http.Client{Transport: recorder}))
+		pokemon, err := client.GetPokemon("pikachu")
+		client.Close()
+		if err != nil || pokemon.Name != "pikachu" {
+			t.Fatalf("expected pikachu, got %+v, %v", pokemon, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected only the recording to reach the server, got %v requests", requests)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.http"))
+	if len(files) != 1 || !strings.HasSuffix(files[0], "_pokemon_pikachu.http") {
+		t.Fatalf("expected one pikachu fixture, got %v", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil || !strings.HasPrefix(string(data), "HTTP/1.1 200 OK") {
+		t.Errorf("expected a raw HTTP response, got %q, %v", data, err)
+	}
+}
+
+func TestRecorderMissingFixture(t *testing.T) {
+	recorder := NewRecorder(t.TempDir(), ModeReplay, nil)
+	attempts := 0
+	client := NewClient(
+		WithHTTPClient(&http.Client{Transport: recorder}),
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Second,
+			MaxDelay:    time.Second,
+			OnAttempt:   func(Attempt) { attempts++ },
+		}),
+	)
+	defer client.Close()
+
+	_, err := client.GetPokemon("pikachu")
+	if !errors.Is(err, ErrNoFixture) {
+		t.Errorf("expected ErrNoFixture, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected a missing fixture not to be retried, got %v attempts", attempts)
+	}
+}
+
+func TestFixtureName(t *testing.T) {
+	cases := []struct {
+		url    string
+		expect string
+	}{
+		{url: "https://pokeapi.co/api/v2/pokemon/pikachu/", expect: "pokeapi.co_api_v2_pokemon_pikachu.http"},
+		{url: "https://pokeapi.co/api/v2/location-area/?offset=20&limit=20", expect: "pokeapi.co_api_v2_location-area_offset=20_limit=20.http"},
+		{url: "https://www.example.com", expect: "www.example.com.http"},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(http.MethodGet, c.url, nil)
+		if got := fixtureName(req); got != c.expect {
+			t.Errorf("fixtureName(%v) = %v, expected %v", c.url, got, c.expect)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Reference to a named PokeAPI resource, e.g. {"name": "pikachu", "url": ".../pokemon/25/"}
+type NamedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Reference to an unnamed PokeAPI resource, such as an evolution chain
+type APIResource struct {
+	URL string `json:"url"`
+}
+
+// Anything that points at a PokeAPI resource
+type Reference interface {
+	ResourceURL() string
+}
+
+func (r NamedAPIResource) ResourceURL() string {
+	return r.URL
+}
+
+func (r APIResource) ResourceURL() string {
+	return r.URL
+}
+
+// Fetch fullURL through the client's cache and decode it into a T
+func Get[T any](ctx context.Context, c *Client, fullURL string) (*T, error) {
+	bytes, err := c.FetchBytesContext(ctx, fullURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var resource T
+	err = json.Unmarshal(bytes, &resource)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling bytes: %v", err)
+	}
+	return &resource, nil
+}
+
+// Follow a reference into its typed model, e.g.
+// Resolve[PokemonSpecies](ctx, client, pokemon.Species)
+func Resolve[T any](ctx context.Context, c *Client, ref Reference) (*T, error) {
+	if ref.ResourceURL() == "" {
+		return nil, fmt.Errorf("error resolving reference: empty url")
+	}
+	return Get[T](ctx, c, ref.ResourceURL())
+}
@@ -0,0 +1,51 @@
+package pokeapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/pokemon/pikachu":
+			w.Write([]byte(`{"name":"pikachu","species":{"name":"pikachu","url":"` + server.URL + `/pokemon-species/25/"}}`))
+		case "/pokemon-species/25/":
+			w.Write([]byte(`{"name":"pikachu","capture_rate":190,"evolution_chain":{"url":"` + server.URL + `/evolution-chain/10/"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+	ctx := context.Background()
+
+	pokemon, err := Get[Pokemon](ctx, client, server.URL+"/pokemon/pikachu")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	for range 2 {
+		species, err := Resolve[PokemonSpecies](ctx, client, pokemon.Species)
+		if err != nil {
+			t.Fatalf("Resolve returned error: %v", err)
+		}
+		if species.CaptureRate != 190 || species.EvolutionChain.ResourceURL() != server.URL+"/evolution-chain/10/" {
+			t.Errorf("unexpected species %+v", species)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected the resolved species to be cached, got %v requests", requests)
+	}
+
+	if _, err := Resolve[PokemonSpecies](ctx, client, NamedAPIResource{Name: "missing"}); err == nil {
+		t.Errorf("expected error resolving a reference without a url")
+	}
+}
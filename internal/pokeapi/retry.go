@@ -0,0 +1,138 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokecache"
+)
+
+// Controls how FetchBytes retries transient failures. Only GET requests are
+// made by the client, so every request is safe to retry.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first, 1 or less disables retries
+	BaseDelay   time.Duration // backoff before the second attempt, doubled after each failure
+	MaxDelay    time.Duration // upper bound on backoff and on an honored Retry-After
+	OnAttempt   func(Attempt) // optional hook called after every attempt
+}
+
+// Describes a finished attempt, passed to RetryPolicy.OnAttempt
+type Attempt struct {
+	URL    string
+	Number int           // 1 for the first attempt
+	Err    error         // nil when the attempt succeeded
+	Delay  time.Duration // wait before the next attempt, 0 when there is none
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// Performs the GET until it succeeds, fails permanently, or runs out of attempts
+func (c *Client) getWithRetry(ctx context.Context, url string, validators pokecache.Validators) (response, error) {
+	req, err := c.newRequest(ctx, url, validators)
+	if err != nil {
+		return response{}, err
+	}
+
+	policy := c.retry
+	for attempt := 1; ; attempt++ {
+		res, err := c.do(req)
+
+		delay, retry := policy.next(attempt, err)
+		if policy.OnAttempt != nil {
+			policy.OnAttempt(Attempt{URL: url, Number: attempt, Err: err, Delay: delay})
+		}
+		if !retry {
+			return res, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Decides whether another attempt should be made after attempt failed with err,
+// and how long to wait before making it
+func (p RetryPolicy) next(attempt int, err error) (time.Duration, bool) {
+	if err == nil || attempt >= p.MaxAttempts || !isRetryable(err) {
+		return 0, false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		// Retrying sooner than the server asked only earns another 429/503
+		if statusErr.RetryAfter > p.MaxDelay {
+			return 0, false
+		}
+		return statusErr.RetryAfter, true
+	}
+	return p.backoff(attempt), true
+}
+
+// Jittered exponential backoff, half fixed and half random so retries spread out
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// Transport errors and 429/5xx responses are transient, everything else is permanent
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	// Reading the bundle or fixtures again will not change the answer
+	if errors.Is(err, ErrNotInBundle) || errors.Is(err, ErrBadBundle) || errors.Is(err, ErrNoFixture) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// Parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
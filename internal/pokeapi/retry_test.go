@@ -0,0 +1,149 @@
+package pokeapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchBytesRetry(t *testing.T) {
+	cases := []struct {
+		name           string
+		statuses       []int // response status per request, 200 once exhausted
+		retryAfter     string
+		maxAttempts    int
+		maxDelay       time.Duration // 10ms when zero
+		expectAttempts int
+		expectDelay    time.Duration // delay before the second attempt, when set
+		expectError    error
+	}{
+		{
+			name:           "recovers after server errors",
+			statuses:       []int{http.StatusServiceUnavailable, http.StatusBadGateway},
+			maxAttempts:    3,
+			expectAttempts: 3,
+		},
+		{
+			name:           "gives up after max attempts",
+			statuses:       []int{500, 500, 500, 500},
+			maxAttempts:    3,
+			expectAttempts: 3,
+			expectError:    ErrServer,
+		},
+		{
+			name:           "does not retry not found",
+			statuses:       []int{http.StatusNotFound},
+			maxAttempts:    3,
+			expectAttempts: 1,
+			expectError:    ErrNotFound,
+		},
+		{
+			name:           "honors short retry-after",
+			statuses:       []int{http.StatusTooManyRequests},
+			retryAfter:     "1",
+			maxAttempts:    3,
+			maxDelay:       2 * time.Second,
+			expectAttempts: 2,
+			expectDelay:    time.Second,
+		},
+		{
+			name:           "stops when retry-after exceeds max delay",
+			statuses:       []int{http.StatusTooManyRequests},
+			retryAfter:     "120",
+			maxAttempts:    3,
+			expectAttempts: 1,
+			expectError:    ErrRateLimited,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= len(c.statuses) {
+					if c.retryAfter != "" {
+						w.Header().Set("Retry-After", c.retryAfter)
+					}
+					w.WriteHeader(c.statuses[requests-1])
+					return
+				}
+				w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			maxDelay := c.maxDelay
+			if maxDelay == 0 {
+				maxDelay = 10 * time.Millisecond
+			}
+			var attempts []Attempt
+			client := NewClient(WithRetryPolicy(RetryPolicy{
+				MaxAttempts: c.maxAttempts,
+				BaseDelay:   time.Millisecond,
+				MaxDelay:    maxDelay,
+				OnAttempt: func(a Attempt) {
+					attempts = append(attempts, a)
+				},
+			}))
+			defer client.Close()
+
+			body, err := client.FetchBytes(server.URL)
+			if c.expectError != nil {
+				if !errors.Is(err, c.expectError) {
+					t.Fatalf("expected %v, got %v", c.expectError, err)
+				}
+			} else if err != nil || string(body) != "ok" {
+				t.Fatalf("expected body 'ok', got %q, %v", body, err)
+			}
+
+			if len(attempts) != c.expectAttempts || requests != c.expectAttempts {
+				t.Fatalf("expected %v attempts, hook saw %v and server saw %v", c.expectAttempts, len(attempts), requests)
+			}
+			for i, a := range attempts {
+				if a.Number != i+1 {
+					t.Errorf("expected attempt number %v, got %v", i+1, a.Number)
+				}
+			}
+			if c.expectDelay != 0 && attempts[0].Delay != c.expectDelay {
+				t.Errorf("expected a delay of exactly %v, got %v", c.expectDelay, attempts[0].Delay)
+			}
+			if last := attempts[len(attempts)-1]; last.Delay != 0 {
+				t.Errorf("expected no delay after the final attempt, got %v", last.Delay)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{header: "", expected: 0},
+		{header: "3", expected: 3 * time.Second},
+		{header: "-1", expected: 0},
+		{header: "soon", expected: 0},
+		{header: now.Add(90 * time.Second).Format(http.TimeFormat), expected: 90 * time.Second},
+		{header: now.Add(-time.Minute).Format(http.TimeFormat), expected: 0},
+	}
+
+	for _, c := range cases {
+		if actual := parseRetryAfter(c.header, now); actual != c.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, expected %v", c.header, actual, c.expected)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 1; attempt <= 6; attempt++ {
+		ceiling := min(policy.BaseDelay<<(attempt-1), policy.MaxDelay)
+		delay := policy.backoff(attempt)
+		if delay < ceiling/2 || delay > ceiling {
+			t.Errorf("attempt %v: delay %v outside [%v, %v]", attempt, delay, ceiling/2, ceiling)
+		}
+	}
+}
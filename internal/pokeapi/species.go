@@ -0,0 +1,93 @@
+package pokeapi
+
+import (
+	"context"
+	"strings"
+)
+
+// Language used when an entry is missing in the requested one
+const DefaultLanguage = "en"
+
+type PokemonSpecies struct {
+	BaseHappiness      int                `json:"base_happiness"`
+	CaptureRate        int                `json:"capture_rate"`
+	Color              NamedAPIResource   `json:"color"`
+	EggGroups          []NamedAPIResource `json:"egg_groups"`
+	EvolutionChain     APIResource        `json:"evolution_chain"`
+	EvolvesFromSpecies *NamedAPIResource  `json:"evolves_from_species"`
+	FlavorTextEntries  []struct {
+		FlavorText string           `json:"flavor_text"`
+		Language   NamedAPIResource `json:"language"`
+		Version    NamedAPIResource `json:"version"`
+	} `json:"flavor_text_entries"`
+	GenderRate int `json:"gender_rate"`
+	Genera     []struct {
+		Genus    string           `json:"genus"`
+		Language NamedAPIResource `json:"language"`
+	} `json:"genera"`
+	Generation           NamedAPIResource  `json:"generation"`
+	GrowthRate           NamedAPIResource  `json:"growth_rate"`
+	Habitat              *NamedAPIResource `json:"habitat"`
+	HasGenderDifferences bool              `json:"has_gender_differences"`
+	HatchCounter         int               `json:"hatch_counter"`
+	ID                   int               `json:"id"`
+	IsBaby               bool              `json:"is_baby"`
+	IsLegendary          bool              `json:"is_legendary"`
+	IsMythical           bool              `json:"is_mythical"`
+	Name                 string            `json:"name"`
+	Names                []struct {
+		Language NamedAPIResource `json:"language"`
+		Name     string           `json:"name"`
+	} `json:"names"`
+	Order     int              `json:"order"`
+	Shape     NamedAPIResource `json:"shape"`
+	Varieties []struct {
+		IsDefault bool             `json:"is_default"`
+		Pokemon   NamedAPIResource `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+// Get a Pokemon species from the provided name
+func (c *Client) GetPokemonSpecies(name string) (*PokemonSpecies, error) {
+	return c.GetPokemonSpeciesContext(context.Background(), name)
+}
+
+// GetPokemonSpecies with a context that cancels the request
+func (c *Client) GetPokemonSpeciesContext(ctx context.Context, name string) (*PokemonSpecies, error) {
+	fullURL := c.endpointURL("pokemon-species", name)
+	return Get[PokemonSpecies](ctx, c, fullURL)
+}
+
+// Genus in the given language, e.g. "Mouse Pokémon", falling back to DefaultLanguage
+func (s *PokemonSpecies) Genus(language string) string {
+	for _, lang := range fallbackLanguages(language) {
+		for _, genus := range s.Genera {
+			if genus.Language.Name == lang {
+				return genus.Genus
+			}
+		}
+	}
+	return ""
+}
+
+// Most recent Pokedex entry in the given language, falling back to DefaultLanguage.
+// Game line breaks are replaced with spaces.
+func (s *PokemonSpecies) FlavorText(language string) string {
+	for _, lang := range fallbackLanguages(language) {
+		// Entries are ordered oldest game first
+		for i := len(s.FlavorTextEntries) - 1; i >= 0; i-- {
+			entry := s.FlavorTextEntries[i]
+			if entry.Language.Name == lang {
+				return strings.Join(strings.Fields(entry.FlavorText), " ")
+			}
+		}
+	}
+	return ""
+}
+
+func fallbackLanguages(language string) []string {
+	if language == "" || language == DefaultLanguage {
+		return []string{DefaultLanguage}
+	}
+	return []string{language, DefaultLanguage}
+}
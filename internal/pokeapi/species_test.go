@@ -0,0 +1,64 @@
+package pokeapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const pikachuSpeciesJSON = `{
+	"name": "pikachu",
+	"capture_rate": 190,
+	"is_legendary": false,
+	"habitat": {"name": "forest", "url": ""},
+	"evolution_chain": {"url": "https://pokeapi.co/api/v2/evolution-chain/10/"},
+	"genera": [
+		{"genus": "Mouse Pokémon", "language": {"name": "en", "url": ""}},
+		{"genus": "Pokémon Souris", "language": {"name": "fr", "url": ""}}
+	],
+	"flavor_text_entries": [
+		{"flavor_text": "When several of\nthese POKéMON\fgather, old entry.", "language": {"name": "en", "url": ""}, "version": {"name": "red", "url": ""}},
+		{"flavor_text": "Il stocke\nde l'électricité.", "language": {"name": "fr", "url": ""}, "version": {"name": "x", "url": ""}},
+		{"flavor_text": "It stores\nelectricity\fin its cheeks.", "language": {"name": "en", "url": ""}, "version": {"name": "x", "url": ""}}
+	]
+}`
+
+func TestGetPokemonSpecies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pokemon-species/pikachu" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(pikachuSpeciesJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+
+	species, err := client.GetPokemonSpecies("pikachu")
+	if err != nil {
+		t.Fatalf("GetPokemonSpecies returned error: %v", err)
+	}
+	if species.CaptureRate != 190 || species.Habitat == nil || species.Habitat.Name != "forest" {
+		t.Errorf("unexpected species %+v", species)
+	}
+
+	cases := []struct {
+		language     string
+		expectGenus  string
+		expectFlavor string
+	}{
+		{language: "", expectGenus: "Mouse Pokémon", expectFlavor: "It stores electricity in its cheeks."},
+		{language: "fr", expectGenus: "Pokémon Souris", expectFlavor: "Il stocke de l'électricité."},
+		{language: "ja", expectGenus: "Mouse Pokémon", expectFlavor: "It stores electricity in its cheeks."},
+	}
+	for _, c := range cases {
+		if genus := species.Genus(c.language); genus != c.expectGenus {
+			t.Errorf("Genus(%q) = %q, expected %q", c.language, genus, c.expectGenus)
+		}
+		if flavor := species.FlavorText(c.language); flavor != c.expectFlavor {
+			t.Errorf("FlavorText(%q) = %q, expected %q", c.language, flavor, c.expectFlavor)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package pokeapi
+
+import (
+	"context"
+	"sort"
+)
+
+// Attacking types considered when listing matchups, in PokeAPI id order
+var StandardTypes = []string{
+	"normal", "fighting", "flying", "poison", "ground", "rock", "bug", "ghost", "steel",
+	"fire", "water", "grass", "electric", "psychic", "ice", "dragon", "dark", "fairy",
+}
+
+type Type struct {
+	DamageRelations struct {
+		DoubleDamageFrom []NamedAPIResource `json:"double_damage_from"`
+		DoubleDamageTo   []NamedAPIResource `json:"double_damage_to"`
+		HalfDamageFrom   []NamedAPIResource `json:"half_damage_from"`
+		HalfDamageTo     []NamedAPIResource `json:"half_damage_to"`
+		NoDamageFrom     []NamedAPIResource `json:"no_damage_from"`
+		NoDamageTo       []NamedAPIResource `json:"no_damage_to"`
+	} `json:"damage_relations"`
+	Generation      NamedAPIResource   `json:"generation"`
+	ID              int                `json:"id"`
+	MoveDamageClass *NamedAPIResource  `json:"move_damage_class"`
+	Moves           []NamedAPIResource `json:"moves"`
+	Name            string             `json:"name"`
+	Pokemon         []struct {
+		Pokemon NamedAPIResource `json:"pokemon"`
+		Slot    int              `json:"slot"`
+	} `json:"pokemon"`
+}
+
+// Get a type from the provided name
+func (c *Client) GetType(name string) (*Type, error) {
+	return c.GetTypeContext(context.Background(), name)
+}
+
+// GetType with a context that cancels the request
+func (c *Client) GetTypeContext(ctx context.Context, name string) (*Type, error) {
+	fullURL := c.endpointURL("type", name)
+	return Get[Type](ctx, c, fullURL)
+}
+
+// Type effectiveness built from the damage relations of added types
+type TypeChart struct {
+	// multipliers[attacking][defending], missing pairs deal normal damage
+	multipliers map[string]map[string]float64
+}
+
+// An attacking type and its damage multiplier against a defender
+type Matchup struct {
+	Type       string
+	Multiplier float64
+}
+
+func NewTypeChart() *TypeChart {
+	return &TypeChart{
+		multipliers: make(map[string]map[string]float64),
+	}
+}
+
+// Record the damage relations of t, both as attacker and defender
+func (tc *TypeChart) Add(t *Type) {
+	relations := t.DamageRelations
+	for _, from := range relations.DoubleDamageFrom {
+		tc.set(from.Name, t.Name, 2)
+	}
+	for _, from := range relations.HalfDamageFrom {
+		tc.set(from.Name, t.Name, 0.5)
+	}
+	for _, from := range relations.NoDamageFrom {
+		tc.set(from.Name, t.Name, 0)
+	}
+	for _, to := range relations.DoubleDamageTo {
+		tc.set(t.Name, to.Name, 2)
+	}
+	for _, to := range relations.HalfDamageTo {
+		tc.set(t.Name, to.Name, 0.5)
+	}
+	for _, to := range relations.NoDamageTo {
+		tc.set(t.Name, to.Name, 0)
+	}
+}
+
+func (tc *TypeChart) set(attacking, defending string, multiplier float64) {
+	if tc.multipliers[attacking] == nil {
+		tc.multipliers[attacking] = make(map[string]float64)
+	}
+	tc.multipliers[attacking][defending] = multiplier
+}
+
+// Damage multiplier of an attacking type against one or more defending
+// types, e.g. 4 for rock against fire/flying
+func (tc *TypeChart) Multiplier(attacking string, defending ...string) float64 {
+	multiplier := 1.0
+	for _, d := range defending {
+		if m, ok := tc.multipliers[attacking][d]; ok {
+			multiplier *= m
+		}
+	}
+	return multiplier
+}
+
+// Multiplier of every standard attacking type against the defending types,
+// strongest first. Ties keep StandardTypes order.
+func (tc *TypeChart) Matchups(defending ...string) []Matchup {
+	matchups := make([]Matchup, 0, len(StandardTypes))
+	for _, attacking := range StandardTypes {
+		matchups = append(matchups, Matchup{
+			Type:       attacking,
+			Multiplier: tc.Multiplier(attacking, defending...),
+		})
+	}
+	sort.SliceStable(matchups, func(i, j int) bool {
+		return matchups[i].Multiplier > matchups[j].Multiplier
+	})
+	return matchups
+}
+
+// Build a chart holding the damage relations of the named types
+func (c *Client) GetTypeChart(names ...string) (*TypeChart, error) {
+	return c.GetTypeChartContext(context.Background(), names...)
+}
+
+// GetTypeChart with a context that cancels the requests
+func (c *Client) GetTypeChartContext(ctx context.Context, names ...string) (*TypeChart, error) {
+	chart := NewTypeChart()
+	for _, name := range names {
+		t, err := c.GetTypeContext(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		chart.Add(t)
+	}
+	return chart, nil
+}
@@ -0,0 +1,71 @@
+package pokeapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Damage relations of fire and flying, trimmed to the defending side
+var typeFixtures = map[string]string{
+	"/type/fire": `{"name":"fire","damage_relations":{
+		"double_damage_from":[{"name":"ground"},{"name":"rock"},{"name":"water"}],
+		"half_damage_from":[{"name":"bug"},{"name":"steel"},{"name":"fire"},{"name":"grass"},{"name":"ice"},{"name":"fairy"}],
+		"no_damage_from":[]}}`,
+	"/type/flying": `{"name":"flying","damage_relations":{
+		"double_damage_from":[{"name":"rock"},{"name":"electric"},{"name":"ice"}],
+		"half_damage_from":[{"name":"fighting"},{"name":"bug"},{"name":"grass"}],
+		"no_damage_from":[{"name":"ground"}]}}`,
+}
+
+func TestTypeChart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := typeFixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(fixture))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	defer client.Close()
+
+	chart, err := client.GetTypeChart("fire", "flying")
+	if err != nil {
+		t.Fatalf("GetTypeChart returned error: %v", err)
+	}
+
+	cases := []struct {
+		attacking string
+		defending []string
+		expected  float64
+	}{
+		{attacking: "rock", defending: []string{"fire", "flying"}, expected: 4},
+		{attacking: "water", defending: []string{"fire", "flying"}, expected: 2},
+		{attacking: "ice", defending: []string{"fire", "flying"}, expected: 1},
+		{attacking: "fire", defending: []string{"fire", "flying"}, expected: 0.5},
+		{attacking: "grass", defending: []string{"fire", "flying"}, expected: 0.25},
+		{attacking: "ground", defending: []string{"fire", "flying"}, expected: 0},
+		{attacking: "ground", defending: []string{"fire"}, expected: 2},
+		{attacking: "normal", defending: []string{"fire", "flying"}, expected: 1},
+	}
+	for _, c := range cases {
+		if actual := chart.Multiplier(c.attacking, c.defending...); actual != c.expected {
+			t.Errorf("Multiplier(%v, %v) = %v, expected %v", c.attacking, c.defending, actual, c.expected)
+		}
+	}
+
+	matchups := chart.Matchups("fire", "flying")
+	if len(matchups) != len(StandardTypes) {
+		t.Fatalf("expected %v matchups, got %v", len(StandardTypes), len(matchups))
+	}
+	if first, last := matchups[0], matchups[len(matchups)-1]; first.Type != "rock" || last.Type != "ground" {
+		t.Errorf("expected rock first and ground last, got %+v and %+v", first, last)
+	}
+
+	if _, err := client.GetTypeChart("shadowy"); err == nil {
+		t.Errorf("expected error for unknown type")
+	}
+}
@@ -0,0 +1,343 @@
+package pokecache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultDiskTTL      = 30 * 24 * time.Hour
+	DefaultDiskMaxBytes = 256 << 20
+	diskEntryExt        = ".entry"
+)
+
+// Header line written before the body of every disk entry
+type diskHeader struct {
+	Key        string     `json:"key"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Size       int        `json:"size"`
+	SHA256     string     `json:"sha256"`
+	Validators Validators `json:"validators"`
+}
+
+// Persistent cache storing one file per key under dir. Entries expire after
+// ttl, fail their integrity check if corrupted, and the least recently used
+// are evicted once the files total more than maxBytes.
+type DiskCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+	size     int64 // bytes of entry files on disk
+	mu       sync.Mutex
+}
+
+// Returns the default disk cache directory, the user cache dir + pokedexcli/http
+func DefaultDiskDir() (string, error) {
+	cacheHome, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("error finding user cache directory: %v", err)
+	}
+	return filepath.Join(cacheHome, "pokedexcli", "http"), nil
+}
+
+// Opens the disk cache at dir, creating it if needed. A maxBytes of 0 means unbounded.
+func NewDiskCache(dir string, ttl time.Duration, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating cache directory %v: %v", dir, err)
+	}
+	d := &DiskCache{
+		dir:      dir,
+		ttl:      ttl,
+		maxBytes: maxBytes,
+	}
+
+	files, err := d.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		d.size += f.size
+	}
+	return d, nil
+}
+
+// Directory the entries are stored in
+func (d *DiskCache) Dir() string {
+	return d.dir
+}
+
+func (d *DiskCache) Add(key string, val []byte) {
+	d.AddWithValidators(key, val, Validators{})
+}
+
+// Add an entry that is kept for revalidation after it expires, as long as
+// validators is not empty
+func (d *DiskCache) AddWithValidators(key string, val []byte, validators Validators) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.write(key, val, validators, time.Now())
+}
+
+// Caller must hold d.mu
+func (d *DiskCache) write(key string, val []byte, validators Validators, createdAt time.Time) {
+	sum := sha256.Sum256(val)
+	header, err := json.Marshal(diskHeader{
+		Key:        key,
+		CreatedAt:  createdAt,
+		Size:       len(val),
+		SHA256:     hex.EncodeToString(sum[:]),
+		Validators: validators,
+	})
+	if err != nil {
+		return
+	}
+	data := append(append(header, '\n'), val...)
+	if d.maxBytes > 0 && int64(len(data)) > d.maxBytes {
+		return
+	}
+
+	path := d.path(key)
+	previous := fileSize(path)
+	if err := writeFileAtomic(path, data); err != nil {
+		return
+	}
+	d.size += int64(len(data)) - previous
+	d.evict()
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	val, header, ok := d.read(key)
+	if !ok || time.Since(header.CreatedAt) > d.ttl {
+		return nil, false
+	}
+	return val, true
+}
+
+// Get an entry with its validators, even if it has expired
+func (d *DiskCache) GetStale(key string) ([]byte, Validators, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	val, header, ok := d.read(key)
+	if !ok {
+		return nil, Validators{}, false
+	}
+	return val, header.Validators, true
+}
+
+// Mark an entry fresh again, as if it had just been added
+func (d *DiskCache) Refresh(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if val, header, ok := d.read(key); ok {
+		d.write(key, val, header.Validators, time.Now())
+	}
+}
+
+// Reads and verifies an entry, dropping corrupt ones and expired ones that
+// cannot be revalidated. Caller must hold d.mu.
+func (d *DiskCache) read(key string) ([]byte, diskHeader, bool) {
+	path := d.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, diskHeader{}, false
+	}
+
+	val, header, err := decodeDiskEntry(data)
+	expired := err == nil && time.Since(header.CreatedAt) > d.ttl
+	if err != nil || header.Key != key || (expired && header.Validators.Empty()) {
+		d.removeFile(path)
+		return nil, diskHeader{}, false
+	}
+
+	// Access time drives LRU eviction
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return val, header, true
+}
+
+// Removes every entry
+func (d *DiskCache) Clear() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, err := d.files()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error removing cache entry %v: %v", f.path, err)
+		}
+	}
+	d.size = 0
+	return nil
+}
+
+func (d *DiskCache) Delete(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.removeFile(d.path(key))
+}
+
+// Number of entries on disk, including expired ones not yet removed
+func (d *DiskCache) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	files, err := d.files()
+	if err != nil {
+		return 0
+	}
+	return len(files)
+}
+
+// Entries and bytes used on disk
+func (d *DiskCache) Stats() (Stats, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, err := d.files()
+	if err != nil {
+		return Stats{}, err
+	}
+	stats := Stats{Name: "disk", Entries: len(files), Location: d.dir}
+	for _, f := range files {
+		stats.Bytes += f.size
+	}
+	return stats, nil
+}
+
+// Nothing is held open between calls, Close exists to satisfy Store
+func (d *DiskCache) Close() error {
+	return nil
+}
+
+// Entries are named by the hash of their key so any URL is a safe file name
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+diskEntryExt)
+}
+
+type diskFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (d *DiskCache) files() ([]diskFile, error) {
+	dirEntries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cache directory %v: %v", d.dir, err)
+	}
+
+	var files []diskFile
+	for _, entry := range dirEntries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), diskEntryExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, diskFile{
+			path:    filepath.Join(d.dir, entry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	return files, nil
+}
+
+// Removes least recently used entries until within maxBytes. Caller must hold d.mu.
+func (d *DiskCache) evict() {
+	if d.maxBytes <= 0 || d.size <= d.maxBytes {
+		return
+	}
+	files, err := d.files()
+	if err != nil {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	d.size = 0
+	for _, f := range files {
+		d.size += f.size
+	}
+	for _, f := range files {
+		if d.size <= d.maxBytes {
+			return
+		}
+		d.removeFile(f.path)
+	}
+}
+
+// Caller must hold d.mu
+func (d *DiskCache) removeFile(path string) {
+	size := fileSize(path)
+	if err := os.Remove(path); err == nil {
+		d.size -= size
+	}
+}
+
+// Splits an entry into its body and header, verifying the body checksum
+func decodeDiskEntry(data []byte) ([]byte, diskHeader, error) {
+	var header diskHeader
+	line, body, ok := bytes.Cut(data, []byte{'\n'})
+	if !ok {
+		return nil, header, fmt.Errorf("missing header")
+	}
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, header, fmt.Errorf("error unmarshalling header: %v", err)
+	}
+	if len(body) != header.Size {
+		return nil, header, fmt.Errorf("expected %v bytes, got %v", header.Size, len(body))
+	}
+	sum := sha256.Sum256(body)
+	if hex.EncodeToString(sum[:]) != header.SHA256 {
+		return nil, header, fmt.Errorf("checksum mismatch")
+	}
+	return body, header, nil
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// Writes through a temp file so readers never see a partial entry
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
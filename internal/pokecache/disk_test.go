@@ -0,0 +1,138 @@
+package pokecache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDiskAddGet(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	disk.Add("https://example.com/a", []byte("testdata"))
+
+	// A second cache on the same directory sees the entry, like a new session
+	reopened, err := NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	val, ok := reopened.Get("https://example.com/a")
+	if !ok || string(val) != "testdata" {
+		t.Fatalf("expected 'testdata', got %q, %v", val, ok)
+	}
+	if _, ok := reopened.Get("https://example.com/b"); ok {
+		t.Errorf("expected missing key to miss")
+	}
+}
+
+func TestDiskExpired(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), time.Nanosecond, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	disk.Add("key", []byte("testdata"))
+	time.Sleep(time.Millisecond)
+
+	if _, ok := disk.Get("key"); ok {
+		t.Errorf("expected expired entry to miss")
+	}
+	if stats, _ := disk.Stats(); stats.Entries != 0 {
+		t.Errorf("expected expired entry to be removed, got %v entries", stats.Entries)
+	}
+}
+
+func TestDiskCorrupt(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	disk.Add("key", []byte("testdata"))
+
+	path := disk.path("key")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading entry: %v", err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("error writing entry: %v", err)
+	}
+
+	if _, ok := disk.Get("key"); ok {
+		t.Errorf("expected corrupt entry to miss")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected corrupt entry to be removed")
+	}
+}
+
+func TestDiskMaxBytes(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	disk.Add("a", make([]byte, 100))
+	entrySize := fileSize(disk.path("a"))
+
+	// Room for two entries
+	disk.maxBytes = 2*entrySize + entrySize/2
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(disk.path("a"), past, past)
+	disk.Add("b", make([]byte, 100))
+	disk.Add("c", make([]byte, 100))
+
+	if _, ok := disk.Get("a"); ok {
+		t.Errorf("expected least recently used entry to be evicted")
+	}
+	for _, key := range []string{"b", "c"} {
+		if _, ok := disk.Get(key); !ok {
+			t.Errorf("expected to find %v", key)
+		}
+	}
+}
+
+func TestDiskClearStats(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	disk.Add("a", []byte("one"))
+	disk.Add("b", []byte("two"))
+
+	stats, err := disk.Stats()
+	if err != nil {
+		t.Fatalf("Stats returned error: %v", err)
+	}
+	if stats.Entries != 2 || stats.Bytes == 0 || stats.Location != dir {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	if err := disk.Clear(); err != nil {
+		t.Fatalf("Clear returned error: %v", err)
+	}
+	if stats, _ := disk.Stats(); stats.Entries != 0 {
+		t.Errorf("expected no entries after clear, got %v", stats.Entries)
+	}
+}
+
+func TestDiskRevalidate(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), time.Nanosecond, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	validators := Validators{ETag: `"abc"`, LastModified: "Mon, 02 Jan 2006 15:04:05 GMT"}
+	disk.AddWithValidators("key", []byte("testdata"), validators)
+	time.Sleep(time.Millisecond)
+
+	if _, ok := disk.Get("key"); ok {
+		t.Errorf("expected expired entry to miss")
+	}
+	val, got, ok := disk.GetStale("key")
+	if !ok || string(val) != "testdata" || got != validators {
+		t.Fatalf("expected stale entry with validators, got %q, %+v, %v", val, got, ok)
+	}
+}
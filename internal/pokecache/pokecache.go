@@ -1,61 +1,243 @@
 package pokecache
 
 import (
+	"container/list"
+	"context"
 	"sync"
 	"time"
 )
 
 type cacheEntry struct {
-	createdAt time.Time
-	val       []byte
+	key        string
+	createdAt  time.Time
+	val        []byte
+	validators Validators
+	stale      bool // past its interval but kept for revalidation
 }
 
 type Cache struct {
-	entries map[string]cacheEntry
-	mu      sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // front is most recently used, values are *cacheEntry
+	size       int        // total bytes of all values
+	maxEntries int        // 0 means unbounded
+	maxBytes   int        // 0 means unbounded
+	mu         sync.Mutex
+
+	stop      context.CancelFunc // stops the reaper
+	done      chan struct{}      // closed once the reaper has exited
+	closeOnce sync.Once
+}
+
+// Configures a Cache built by NewCache
+type Option func(*Cache)
+
+// Evict the least recently used entry once the cache holds more than n entries
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) {
+		c.maxEntries = n
+	}
 }
 
-func NewCache(interval time.Duration) *Cache {
+// Evict least recently used entries once values total more than n bytes.
+// A single value larger than n is never stored.
+func WithMaxBytes(n int) Option {
+	return func(c *Cache) {
+		c.maxBytes = n
+	}
+}
+
+// Creates a cache whose entries are reaped after interval, 0 or less never reaps.
+// Entries with validators are kept stale for one more interval so they can be
+// revalidated, then reaped too.
+func NewCache(interval time.Duration, opts ...Option) *Cache {
 	cache := &Cache{
-		entries: make(map[string]cacheEntry),
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+	for _, opt := range opts {
+		opt(cache)
 	}
-	go cache.readLoop(interval)
+
+	ctx, stop := context.WithCancel(context.Background())
+	cache.stop = stop
+	cache.done = make(chan struct{})
+	if interval <= 0 {
+		// No TTL, entries only leave through eviction
+		close(cache.done)
+		return cache
+	}
+	go cache.readLoop(ctx, interval)
 	return cache
 }
 
+// Stops the reaper and waits for it to exit. Entries stay readable but are
+// no longer reaped by age. Safe to call more than once.
+func (c *Cache) Close() error {
+	c.closeOnce.Do(func() {
+		c.stop()
+		<-c.done
+	})
+	return nil
+}
+
 func (c *Cache) Add(key string, val []byte) {
+	c.AddWithValidators(key, val, Validators{})
+}
+
+// Add an entry that is kept for revalidation for an interval after it goes
+// stale, as long as validators is not empty
+func (c *Cache) AddWithValidators(key string, val []byte, validators Validators) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	entry := cacheEntry{
-		createdAt: time.Now(),
-		val:       val,
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
 	}
-	c.entries[key] = entry
+	if c.maxBytes > 0 && len(val) > c.maxBytes {
+		return
+	}
+
+	entry := &cacheEntry{
+		key:        key,
+		createdAt:  time.Now(),
+		val:        val,
+		validators: validators,
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	c.size += len(val)
+	c.evict()
 }
 
 func (c *Cache) Get(key string) ([]byte, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	entry, ok := c.entries[key]
-	if !ok {
-		return nil, ok
+	elem, ok := c.entries[key]
+	if !ok || elem.Value.(*cacheEntry).stale {
+		return nil, false
 	}
+	c.lru.MoveToFront(elem)
 
+	entry := elem.Value.(*cacheEntry)
 	valCopy := make([]byte, len(entry.val))
 	copy(valCopy, entry.val)
 	return valCopy, ok
 }
 
-func (c *Cache) readLoop(interval time.Duration) {
-	for tick := range time.Tick(interval) {
-		c.mu.Lock()
-		for k, v := range c.entries {
-			// Reap the entry
-			if tick.Sub(v.createdAt) > interval {
-				delete(c.entries, k)
-			}
+// Get an entry with its validators, even if it is stale
+func (c *Cache) GetStale(key string) ([]byte, Validators, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, Validators{}, false
+	}
+	c.lru.MoveToFront(elem)
+
+	entry := elem.Value.(*cacheEntry)
+	valCopy := make([]byte, len(entry.val))
+	copy(valCopy, entry.val)
+	return valCopy, entry.validators, true
+}
+
+// Mark an entry fresh again, as if it had just been added
+func (c *Cache) Refresh(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.createdAt = time.Now()
+		entry.stale = false
+	}
+}
+
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// Removes every entry
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.size = 0
+	return nil
+}
+
+// Number of entries in the cache
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Total bytes of all values in the cache
+func (c *Cache) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *Cache) Stats() (Stats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{Name: "memory", Entries: c.lru.Len(), Bytes: int64(c.size)}, nil
+}
+
+// Drops least recently used entries until the cache is within its limits.
+// Caller must hold c.mu.
+func (c *Cache) evict() {
+	for c.lru.Len() > 0 &&
+		((c.maxEntries > 0 && c.lru.Len() > c.maxEntries) || (c.maxBytes > 0 && c.size > c.maxBytes)) {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Caller must hold c.mu
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= len(entry.val)
+}
+
+// Reaps entries older than interval until ctx is cancelled
+func (c *Cache) readLoop(ctx context.Context, interval time.Duration) {
+	defer close(c.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tick := <-ticker.C:
+			c.reap(tick, interval)
+		}
+	}
+}
+
+func (c *Cache) reap(now time.Time, interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, elem := range c.entries {
+		entry := elem.Value.(*cacheEntry)
+		age := now.Sub(entry.createdAt)
+		if age <= interval {
+			continue
+		}
+		// Entries with validators can be revalidated cheaply, keep them stale
+		// for another interval
+		if entry.validators.Empty() || (entry.stale && age > 2*interval) {
+			c.remove(elem)
+		} else {
+			entry.stale = true
 		}
-		c.mu.Unlock()
 	}
 }
@@ -2,6 +2,7 @@ package pokecache
 
 import (
 	"fmt"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -33,6 +34,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := NewCache(interval)
+			defer cache.Close()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -51,6 +53,7 @@ func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
 	cache := NewCache(baseTime)
+	defer cache.Close()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -67,3 +70,156 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 }
+
+func TestLRUEviction(t *testing.T) {
+	cases := []struct {
+		name        string
+		opts        []Option
+		adds        []string // keys added in order, each value is the key itself
+		touch       string   // key read after the adds, marking it recently used
+		extra       string   // key added after touch
+		expectGone  []string
+		expectFound []string
+	}{
+		{
+			name:        "max entries",
+			opts:        []Option{WithMaxEntries(2)},
+			adds:        []string{"a", "b"},
+			touch:       "a",
+			extra:       "c",
+			expectGone:  []string{"b"},
+			expectFound: []string{"a", "c"},
+		},
+		{
+			name:        "max bytes",
+			opts:        []Option{WithMaxBytes(6)},
+			adds:        []string{"aa", "bb", "cc"},
+			touch:       "aa",
+			extra:       "dd",
+			expectGone:  []string{"bb"},
+			expectFound: []string{"aa", "cc", "dd"},
+		},
+		{
+			name:        "value larger than max bytes",
+			opts:        []Option{WithMaxBytes(3)},
+			adds:        []string{"a"},
+			touch:       "a",
+			extra:       "toolarge",
+			expectGone:  []string{"toolarge"},
+			expectFound: []string{"a"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cache := NewCache(time.Minute, c.opts...)
+			defer cache.Close()
+			for _, key := range c.adds {
+				cache.Add(key, []byte(key))
+			}
+			cache.Get(c.touch)
+			cache.Add(c.extra, []byte(c.extra))
+
+			for _, key := range c.expectGone {
+				if _, ok := cache.Get(key); ok {
+					t.Errorf("expected %v to be evicted", key)
+				}
+			}
+			for _, key := range c.expectFound {
+				if _, ok := cache.Get(key); !ok {
+					t.Errorf("expected to find %v", key)
+				}
+			}
+		})
+	}
+}
+
+func TestAddReplaceSize(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+	cache.Add("key", []byte("short"))
+	cache.Add("key", []byte("much longer"))
+
+	if cache.Len() != 1 {
+		t.Errorf("expected 1 entry, got %v", cache.Len())
+	}
+	if cache.Size() != len("much longer") {
+		t.Errorf("expected size %v, got %v", len("much longer"), cache.Size())
+	}
+}
+
+func TestCloseStopsReaper(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	caches := make([]*Cache, 10)
+	for i := range caches {
+		caches[i] = NewCache(time.Millisecond)
+	}
+	if runtime.NumGoroutine() < before+len(caches) {
+		t.Fatalf("expected a reaper goroutine per cache")
+	}
+
+	for _, cache := range caches {
+		cache.Close()
+		cache.Close() // closing twice is a no-op
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected %v goroutines after close, got %v", before, after)
+	}
+}
+
+func TestReapKeepsValidated(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	cache := NewCache(0)
+	defer cache.Close()
+	validators := Validators{ETag: `"abc"`}
+	cache.AddWithValidators("https://example.com", []byte("testdata"), validators)
+
+	cache.reap(time.Now().Add(baseTime+time.Millisecond), baseTime)
+
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected stale entry to miss")
+	}
+	val, got, ok := cache.GetStale("https://example.com")
+	if !ok || string(val) != "testdata" || got != validators {
+		t.Fatalf("expected stale entry with validators, got %q, %+v, %v", val, got, ok)
+	}
+
+	cache.Refresh("https://example.com")
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected refreshed entry to hit")
+	}
+}
+
+func TestReapDropsStale(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	cache := NewCache(baseTime)
+	defer cache.Close()
+	cache.AddWithValidators("https://example.com", []byte("testdata"), Validators{ETag: `"abc"`})
+
+	// Stale entries leave an unbounded cache one interval after going stale
+	time.Sleep(4*baseTime + 10*time.Millisecond)
+
+	if _, _, ok := cache.GetStale("https://example.com"); ok {
+		t.Errorf("expected stale entry to be reaped")
+	}
+	if stats, _ := cache.Stats(); cache.Len() != 0 || stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("expected empty cache, got %v entries, %+v", cache.Len(), stats)
+	}
+}
+
+func TestNoReapInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		cache := NewCache(interval)
+		cache.Add("https://example.com", []byte("testdata"))
+		time.Sleep(5 * time.Millisecond)
+
+		if _, ok := cache.Get("https://example.com"); !ok {
+			t.Errorf("expected entry to be kept with interval %v", interval)
+		}
+		if err := cache.Close(); err != nil {
+			t.Errorf("Close returned error: %v", err)
+		}
+	}
+}
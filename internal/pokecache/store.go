@@ -0,0 +1,197 @@
+package pokecache
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Backend a client caches responses in. Implementations must be safe for
+// concurrent use and treat a stored slice as owned by the store.
+type Store interface {
+	Get(key string) ([]byte, bool)
+	Add(key string, val []byte)
+	Delete(key string)
+	Len() int
+	Close() error
+}
+
+// Optional interface for stores that can remove every entry at once
+type Clearer interface {
+	Clear() error
+}
+
+// Optional interface for stores that report their usage
+type Reporter interface {
+	Stats() (Stats, error)
+}
+
+// HTTP cache validators stored alongside a response
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (v Validators) Empty() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// Optional interface for stores that keep expired entries which can be
+// revalidated with their Validators instead of downloaded again
+type Revalidator interface {
+	AddWithValidators(key string, val []byte, validators Validators)
+	GetStale(key string) ([]byte, Validators, bool)
+	Refresh(key string)
+}
+
+// Usage of a single store
+type Stats struct {
+	Name     string // kind of store, e.g. "memory" or "disk"
+	Entries  int
+	Bytes    int64
+	Location string // where entries are kept, empty for memory stores
+}
+
+var (
+	_ Revalidator = (*Cache)(nil)
+	_ Revalidator = (*DiskCache)(nil)
+	_ Revalidator = (*Tiered)(nil)
+
+	_ Store = (*Cache)(nil)
+	_ Store = (*DiskCache)(nil)
+	_ Store = (*Tiered)(nil)
+	_ Store = Nop{}
+)
+
+// Store that keeps nothing, used to disable caching
+type Nop struct{}
+
+func (Nop) Get(key string) ([]byte, bool) { return nil, false }
+func (Nop) Add(key string, val []byte)    {}
+func (Nop) Delete(key string)             {}
+func (Nop) Len() int                      { return 0 }
+func (Nop) Clear() error                  { return nil }
+func (Nop) Close() error                  { return nil }
+
+// Layers stores from fastest to slowest. Reads fall through the tiers and
+// promote hits into the faster ones, writes go to every tier.
+type Tiered struct {
+	tiers []Store
+}
+
+func NewTiered(tiers ...Store) *Tiered {
+	return &Tiered{tiers: tiers}
+}
+
+// The layered stores, fastest first
+func (t *Tiered) Tiers() []Store {
+	return t.tiers
+}
+
+func (t *Tiered) Get(key string) ([]byte, bool) {
+	for i, tier := range t.tiers {
+		val, ok := tier.Get(key)
+		if !ok {
+			continue
+		}
+		for _, faster := range t.tiers[:i] {
+			faster.Add(key, val)
+		}
+		return val, true
+	}
+	return nil, false
+}
+
+func (t *Tiered) Add(key string, val []byte) {
+	for _, tier := range t.tiers {
+		tier.Add(key, val)
+	}
+}
+
+// Add to every tier, keeping validators in the tiers that support them
+func (t *Tiered) AddWithValidators(key string, val []byte, validators Validators) {
+	for _, tier := range t.tiers {
+		if revalidator, ok := tier.(Revalidator); ok {
+			revalidator.AddWithValidators(key, val, validators)
+		} else {
+			tier.Add(key, val)
+		}
+	}
+}
+
+// Stale entry from the fastest tier that has one
+func (t *Tiered) GetStale(key string) ([]byte, Validators, bool) {
+	for _, tier := range t.tiers {
+		if revalidator, ok := tier.(Revalidator); ok {
+			if val, validators, ok := revalidator.GetStale(key); ok {
+				return val, validators, true
+			}
+		}
+	}
+	return nil, Validators{}, false
+}
+
+// Refresh the entry in every tier that supports it
+func (t *Tiered) Refresh(key string) {
+	for _, tier := range t.tiers {
+		if revalidator, ok := tier.(Revalidator); ok {
+			revalidator.Refresh(key)
+		}
+	}
+}
+
+func (t *Tiered) Delete(key string) {
+	for _, tier := range t.tiers {
+		tier.Delete(key)
+	}
+}
+
+// Entries in the slowest tier, which every Add reaches
+func (t *Tiered) Len() int {
+	if len(t.tiers) == 0 {
+		return 0
+	}
+	return t.tiers[len(t.tiers)-1].Len()
+}
+
+// Clears every tier that supports it
+func (t *Tiered) Clear() error {
+	var errs []error
+	for _, tier := range t.tiers {
+		if clearer, ok := tier.(Clearer); ok {
+			errs = append(errs, clearer.Clear())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t *Tiered) Close() error {
+	var errs []error
+	for _, tier := range t.tiers {
+		errs = append(errs, tier.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Usage of every store, flattening tiered stores into their tiers
+func StoreStats(store Store) ([]Stats, error) {
+	if tiered, ok := store.(*Tiered); ok {
+		var all []Stats
+		for _, tier := range tiered.Tiers() {
+			stats, err := StoreStats(tier)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, stats...)
+		}
+		return all, nil
+	}
+
+	if reporter, ok := store.(Reporter); ok {
+		stats, err := reporter.Stats()
+		if err != nil {
+			return nil, err
+		}
+		return []Stats{stats}, nil
+	}
+	return []Stats{{Name: fmt.Sprintf("%T", store), Entries: store.Len()}}, nil
+}
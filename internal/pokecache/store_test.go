@@ -0,0 +1,81 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTieredPromotes(t *testing.T) {
+	memory := NewCache(time.Minute)
+	disk, err := NewDiskCache(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	tiered := NewTiered(memory, disk)
+	defer tiered.Close()
+
+	disk.Add("key", []byte("testdata"))
+	if _, ok := memory.Get("key"); ok {
+		t.Fatalf("expected key to only be on disk")
+	}
+
+	val, ok := tiered.Get("key")
+	if !ok || string(val) != "testdata" {
+		t.Fatalf("expected 'testdata', got %q, %v", val, ok)
+	}
+	if _, ok := memory.Get("key"); !ok {
+		t.Errorf("expected disk hit to be promoted into memory")
+	}
+
+	tiered.Delete("key")
+	if _, ok := tiered.Get("key"); ok {
+		t.Errorf("expected key to be deleted from every tier")
+	}
+}
+
+func TestTieredAddClear(t *testing.T) {
+	memory := NewCache(time.Minute)
+	disk, err := NewDiskCache(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	tiered := NewTiered(memory, disk)
+	defer tiered.Close()
+
+	tiered.Add("a", []byte("one"))
+	tiered.Add("b", []byte("two"))
+	if memory.Len() != 2 || disk.Len() != 2 || tiered.Len() != 2 {
+		t.Fatalf("expected 2 entries in every tier, got memory=%v disk=%v tiered=%v", memory.Len(), disk.Len(), tiered.Len())
+	}
+
+	stats, err := StoreStats(tiered)
+	if err != nil {
+		t.Fatalf("StoreStats returned error: %v", err)
+	}
+	if len(stats) != 2 || stats[0].Name != "memory" || stats[1].Name != "disk" {
+		t.Errorf("expected memory and disk stats, got %+v", stats)
+	}
+
+	if err := tiered.Clear(); err != nil {
+		t.Fatalf("Clear returned error: %v", err)
+	}
+	if memory.Len() != 0 || disk.Len() != 0 {
+		t.Errorf("expected every tier to be empty, got memory=%v disk=%v", memory.Len(), disk.Len())
+	}
+}
+
+func TestNop(t *testing.T) {
+	var store Store = Nop{}
+	store.Add("key", []byte("testdata"))
+	if _, ok := store.Get("key"); ok {
+		t.Errorf("expected Nop to never hit")
+	}
+	if store.Len() != 0 {
+		t.Errorf("expected Nop to be empty")
+	}
+
+	stats, err := StoreStats(store)
+	if err != nil || len(stats) != 1 || stats[0].Entries != 0 {
+		t.Errorf("unexpected stats %+v, %v", stats, err)
+	}
+}
@@ -0,0 +1,184 @@
+package repl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokeapi"
+	"github.com/evanwiseman/pokedexcli/internal/pokecache"
+)
+
+// Base of the URLs handed out by fakeAPI
+const fakeBaseURL = "fake://pokeapi/"
+
+// In-memory PokeAPI for command tests. Missing resources are 404s and err,
+// when set, is returned by every call instead.
+type fakeAPI struct {
+	areas     []pokeapi.LocationArea // in list order
+	pokemon   map[string]pokeapi.Pokemon
+	species   map[string]pokeapi.PokemonSpecies
+	chains    map[string]pokeapi.EvolutionChain // by species name
+	types     map[string]pokeapi.Type
+	moves     map[string]pokeapi.Move
+	abilities map[string]pokeapi.Ability
+	cached    int // entries reported by CacheStats, reset by ClearCache
+	err       error
+}
+
+var _ PokeAPI = (*fakeAPI)(nil)
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		pokemon:   make(map[string]pokeapi.Pokemon),
+		species:   make(map[string]pokeapi.PokemonSpecies),
+		chains:    make(map[string]pokeapi.EvolutionChain),
+		types:     make(map[string]pokeapi.Type),
+		moves:     make(map[string]pokeapi.Move),
+		abilities: make(map[string]pokeapi.Ability),
+	}
+}
+
+// Decode a JSON literal into a model, for models with nested anonymous structs
+func decode[T any](t *testing.T, data string) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("error decoding %T: %v", v, err)
+	}
+	return v
+}
+
+func notFound(resource, name string) error {
+	return &pokeapi.StatusError{StatusCode: 404, URL: fakeBaseURL + resource + "/" + name}
+}
+
+func (f *fakeAPI) LocationAreaURL() string {
+	return fakeBaseURL + "location-area/"
+}
+
+// Pages of 20 areas, with offset in the URL's query like PokeAPI
+func (f *fakeAPI) GetLocationAreaListContext(ctx context.Context, fullURL string) (*pokeapi.LocationAreaList, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	u, err := url.Parse(fullURL)
+	if err != nil {
+		return nil, err
+	}
+	const limit = 20
+	offset, _ := strconv.Atoi(u.Query().Get("offset"))
+
+	page := &pokeapi.LocationAreaList{Count: len(f.areas)}
+	for i := offset; i < len(f.areas) && i < offset+limit; i++ {
+		page.Results = append(page.Results, pokeapi.NamedAPIResource{
+			Name: f.areas[i].Name,
+			URL:  fmt.Sprintf("%vlocation-area/%v/", fakeBaseURL, f.areas[i].ID),
+		})
+	}
+	if offset+limit < len(f.areas) {
+		next := fmt.Sprintf("%v?offset=%v", f.LocationAreaURL(), offset+limit)
+		page.Next = &next
+	}
+	if offset > 0 {
+		previous := fmt.Sprintf("%v?offset=%v", f.LocationAreaURL(), max(offset-limit, 0))
+		page.Previous = &previous
+	}
+	return page, nil
+}
+
+func (f *fakeAPI) GetLocationAreaContext(ctx context.Context, name string) (*pokeapi.LocationArea, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, area := range f.areas {
+		if area.Name == name {
+			return &area, nil
+		}
+	}
+	return nil, notFound("location-area", name)
+}
+
+func (f *fakeAPI) GetPokemonContext(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+	return get(f, f.pokemon, "pokemon", name)
+}
+
+func (f *fakeAPI) GetPokemonBatch(ctx context.Context, names []string) []pokeapi.BatchResult[pokeapi.Pokemon] {
+	results := make([]pokeapi.BatchResult[pokeapi.Pokemon], len(names))
+	for i, name := range names {
+		pokemon, err := get(f, f.pokemon, "pokemon", name)
+		results[i] = pokeapi.BatchResult[pokeapi.Pokemon]{Value: pokemon, Err: err}
+	}
+	return results
+}
+
+func (f *fakeAPI) GetPokemonSpeciesContext(ctx context.Context, name string) (*pokeapi.PokemonSpecies, error) {
+	return get(f, f.species, "pokemon-species", name)
+}
+
+func (f *fakeAPI) GetSpeciesEvolutionChainContext(ctx context.Context, species string) (*pokeapi.EvolutionChain, error) {
+	return get(f, f.chains, "pokemon-species", species)
+}
+
+func (f *fakeAPI) GetTypeChartContext(ctx context.Context, names ...string) (*pokeapi.TypeChart, error) {
+	chart := pokeapi.NewTypeChart()
+	for _, name := range names {
+		t, err := get(f, f.types, "type", name)
+		if err != nil {
+			return nil, err
+		}
+		chart.Add(t)
+	}
+	return chart, nil
+}
+
+func (f *fakeAPI) GetMoveBatch(ctx context.Context, names []string) []pokeapi.BatchResult[pokeapi.Move] {
+	results := make([]pokeapi.BatchResult[pokeapi.Move], len(names))
+	for i, name := range names {
+		move, err := get(f, f.moves, "move", name)
+		results[i] = pokeapi.BatchResult[pokeapi.Move]{Value: move, Err: err}
+	}
+	return results
+}
+
+func (f *fakeAPI) GetAbilityContext(ctx context.Context, name string) (*pokeapi.Ability, error) {
+	return get(f, f.abilities, "ability", name)
+}
+
+func (f *fakeAPI) CacheStats() ([]pokecache.Stats, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []pokecache.Stats{{Name: "fake", Entries: f.cached}}, nil
+}
+
+func (f *fakeAPI) ClearCache() error {
+	if f.err != nil {
+		return f.err
+	}
+	f.cached = 0
+	return nil
+}
+
+func (f *fakeAPI) Offline() bool {
+	return false
+}
+
+func (f *fakeAPI) BuildBundle(ctx context.Context, dir, resource string, limit int) (int, error) {
+	return 0, fmt.Errorf("fakeAPI cannot build bundles")
+}
+
+// Look up a copy of name in resources
+func get[T any](f *fakeAPI, resources map[string]T, resource, name string) (*T, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	v, ok := resources[name]
+	if !ok {
+		return nil, notFound(resource, name)
+	}
+	return &v, nil
+}
@@ -0,0 +1,63 @@
+package repl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Turns Ctrl-C into cancellation of the running command instead of killing the process
+type interruptHandler struct {
+	signals chan os.Signal
+	out     io.Writer // where the prompt is redrawn
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+}
+
+func newInterruptHandler(out io.Writer) *interruptHandler {
+	h := &interruptHandler{
+		signals: make(chan os.Signal, 1),
+		out:     out,
+	}
+	signal.Notify(h.signals, os.Interrupt)
+	go h.loop()
+	return h
+}
+
+func (h *interruptHandler) loop() {
+	for range h.signals {
+		h.mu.Lock()
+		if h.cancel != nil {
+			h.cancel()
+		} else {
+			// Nothing running, redraw the prompt
+			fmt.Fprint(h.out, "\n(use 'exit' to quit)\nPokedex > ")
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Starts a command, returns a context cancelled by the next interrupt
+func (h *interruptHandler) begin() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.mu.Lock()
+	h.cancel = cancel
+	h.mu.Unlock()
+	return ctx, cancel
+}
+
+// Marks the running command as finished
+func (h *interruptHandler) end() {
+	h.mu.Lock()
+	h.cancel = nil
+	h.mu.Unlock()
+}
+
+// Restores default interrupt handling
+func (h *interruptHandler) stop() {
+	signal.Stop(h.signals)
+	close(h.signals)
+}
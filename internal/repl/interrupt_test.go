@@ -0,0 +1,67 @@
+package repl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInterruptHandlerCancelsCommand(t *testing.T) {
+	h := newInterruptHandler(io.Discard)
+	defer h.stop()
+
+	ctx, cancel := h.begin()
+	defer cancel()
+
+	h.signals <- os.Interrupt
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected interrupt to cancel the command context")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", ctx.Err())
+	}
+	h.end()
+}
+
+func TestInterruptHandlerRedrawsPrompt(t *testing.T) {
+	var buf syncBuffer
+	h := newInterruptHandler(&buf)
+	defer h.stop()
+
+	h.signals <- os.Interrupt
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "Pokedex > ") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the prompt to be redrawn on out, got %q", buf.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// bytes.Buffer safe for the handler's goroutine to write while the test reads
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,33 @@
+package repl
+
+import (
+	"context"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokeapi"
+	"github.com/evanwiseman/pokedexcli/internal/pokecache"
+)
+
+// The PokeAPI endpoints used by commands, implemented by *pokeapi.Client.
+// Tests can swap in a fake so commands run without a server.
+type PokeAPI interface {
+	// URL of the first page of location areas, where 'map' starts
+	LocationAreaURL() string
+	GetLocationAreaListContext(ctx context.Context, fullURL string) (*pokeapi.LocationAreaList, error)
+	GetLocationAreaContext(ctx context.Context, name string) (*pokeapi.LocationArea, error)
+	GetPokemonContext(ctx context.Context, name string) (*pokeapi.Pokemon, error)
+	GetPokemonBatch(ctx context.Context, names []string) []pokeapi.BatchResult[pokeapi.Pokemon]
+	GetPokemonSpeciesContext(ctx context.Context, name string) (*pokeapi.PokemonSpecies, error)
+	GetSpeciesEvolutionChainContext(ctx context.Context, species string) (*pokeapi.EvolutionChain, error)
+	GetTypeChartContext(ctx context.Context, names ...string) (*pokeapi.TypeChart, error)
+	GetMoveBatch(ctx context.Context, names []string) []pokeapi.BatchResult[pokeapi.Move]
+	GetAbilityContext(ctx context.Context, name string) (*pokeapi.Ability, error)
+
+	CacheStats() ([]pokecache.Stats, error)
+	ClearCache() error
+
+	// Offline reports whether requests are served from a bundle
+	Offline() bool
+	BuildBundle(ctx context.Context, dir, resource string, limit int) (int, error)
+}
+
+var _ PokeAPI = (*pokeapi.Client)(nil)
@@ -2,22 +2,72 @@ package repl
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/evanwiseman/pokedexcli/internal/pokeapi"
+	"github.com/evanwiseman/pokedexcli/internal/savefile"
 )
 
+// Splits input into words, lowercasing the command word. Arguments keep their
+// case so paths survive, commands lowercase the PokeAPI names they take.
 func CleanInput(text string) []string {
-	return strings.Fields(strings.ToLower(text))
+	words := strings.Fields(text)
+	if len(words) > 0 {
+		words[0] = strings.ToLower(words[0])
+	}
+	return words
 }
 
 type Context struct {
-	Client         *pokeapi.Client
-	LocationConfig *pokeapi.Config
+	Client         PokeAPI
+	LocationConfig *PageConfig
 	Pokedex        map[string]pokeapi.Pokemon
+	SavePath       string    // autosave location, empty disables autosave
+	Language       string    // language of Pokedex entries, empty means pokeapi.DefaultLanguage
+	Out            io.Writer // command output, nil means os.Stdout
+	ErrOut         io.Writer // errors and warnings, nil means os.Stderr
+
+	cmdCtx           context.Context // cancelled when the user interrupts the running command
+	handleInterrupts bool            // Run turns Ctrl-C into cancellation, set by Start
+}
+
+// Writer for command output
+func (c *Context) out() io.Writer {
+	if c == nil || c.Out == nil {
+		return os.Stdout
+	}
+	return c.Out
+}
+
+// Writer for errors and warnings
+func (c *Context) errOut() io.Writer {
+	if c == nil || c.ErrOut == nil {
+		return os.Stderr
+	}
+	return c.ErrOut
+}
+
+// Context of the running command, Background when none is set
+func (c *Context) commandContext() context.Context {
+	if c.cmdCtx == nil {
+		return context.Background()
+	}
+	return c.cmdCtx
+}
+
+// Paging state of the map/mapb commands
+type PageConfig struct {
+	Next     *string
+	Previous *string
 }
 
 type CliCommand struct {
@@ -51,7 +101,7 @@ func GetCommandRegistry() map[string]CliCommand {
 		},
 		"explore": {
 			Name:        "explore",
-			Description: "Explore an area, gets a list of all pokemon in the area",
+			Description: "Explore an area, gets a list of all pokemon in the area and their types",
 			Callback:    CommandExplore,
 		},
 		"catch": {
@@ -69,24 +119,83 @@ func GetCommandRegistry() map[string]CliCommand {
 			Description: "Lists all caught Pokemon in your Pokedex",
 			Callback:    CommandPokedex,
 		},
+		"evolutions": {
+			Name:        "evolutions",
+			Description: "Shows the evolution tree of a Pokemon",
+			Callback:    CommandEvolutions,
+		},
+		"weakness": {
+			Name:        "weakness",
+			Description: "Lists the type matchups of a Pokemon",
+			Callback:    CommandWeakness,
+		},
+		"moves": {
+			Name:        "moves",
+			Description: "Lists the moves a Pokemon learns, filter with --version-group and --method",
+			Callback:    CommandMoves,
+		},
+		"ability": {
+			Name:        "ability",
+			Description: "Describes an ability and lists the Pokemon that can have it",
+			Callback:    CommandAbility,
+		},
+		"cache": {
+			Name:        "cache",
+			Description: "Shows cache usage with 'cache stats' or empties it with 'cache clear'",
+			Callback:    CommandCache,
+		},
+		"bundle": {
+			Name:        "bundle",
+			Description: "Saves resources for offline use with 'bundle build <dir> <resource>...', limit each with --limit",
+			Callback:    CommandBundle,
+		},
+		"save": {
+			Name:        "save",
+			Description: "Saves your Pokedex to disk, optionally to a given path",
+			Callback:    CommandSave,
+		},
+		"load": {
+			Name:        "load",
+			Description: "Loads your Pokedex from disk, optionally from a given path that later autosaves go to",
+			Callback:    CommandLoad,
+		},
+	}
+}
+
+// Rewrites PokeAPI errors the user can act on, others are returned unchanged
+func friendlyError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("cancelled")
+	case errors.Is(err, pokeapi.ErrRateLimited):
+		return fmt.Errorf("PokeAPI is rate limiting requests, try again shortly")
+	case errors.Is(err, pokeapi.ErrServer):
+		return fmt.Errorf("PokeAPI is having trouble, try again later: %v", err)
+	}
+	var notInBundle *pokeapi.NotInBundleError
+	if errors.As(err, &notInBundle) {
+		return notInBundle
 	}
+	return err
 }
 
-// Exits the program
+// Returned by CommandExit to end Run
+var errExit = errors.New("exit")
+
+// Exits the REPL
 func CommandExit(ctx *Context, parameters []string) error {
-	fmt.Println("Closing the Pokedex... Goodbye!")
-	os.Exit(0)
-	return fmt.Errorf("error exiting program")
+	fmt.Fprintln(ctx.out(), "Closing the Pokedex... Goodbye!")
+	return errExit
 }
 
 // Outputs registry commands and their descriptions
 func CommandHelp(ctx *Context, parameters []string) error {
-	fmt.Println("Welcome to the Pokedex!")
-	fmt.Println("Usage:")
-	fmt.Println()
+	fmt.Fprintln(ctx.out(), "Welcome to the Pokedex!")
+	fmt.Fprintln(ctx.out(), "Usage:")
+	fmt.Fprintln(ctx.out())
 
 	for _, v := range GetCommandRegistry() {
-		fmt.Printf("%v: %v\n", v.Name, v.Description)
+		fmt.Fprintf(ctx.out(), "%v: %v\n", v.Name, v.Description)
 	}
 
 	return nil
@@ -98,7 +207,7 @@ func CommandMap(ctx *Context, parameters []string) error {
 		return fmt.Errorf("you're on the last page")
 	}
 	url := *ctx.LocationConfig.Next
-	areas, err := ctx.Client.GetLocationAreaList(url)
+	areas, err := ctx.Client.GetLocationAreaListContext(ctx.commandContext(), url)
 	if err != nil {
 		return err
 	}
@@ -107,7 +216,7 @@ func CommandMap(ctx *Context, parameters []string) error {
 	ctx.LocationConfig.Previous = areas.Previous
 
 	for _, result := range areas.Results {
-		fmt.Printf("%s\n", result.Name)
+		fmt.Fprintf(ctx.out(), "%s\n", result.Name)
 	}
 
 	return nil
@@ -119,7 +228,7 @@ func CommandMapb(ctx *Context, parameters []string) error {
 		return fmt.Errorf("you're on the first page")
 	}
 	url := *ctx.LocationConfig.Previous
-	areas, err := ctx.Client.GetLocationAreaList(url)
+	areas, err := ctx.Client.GetLocationAreaListContext(ctx.commandContext(), url)
 	if err != nil {
 		return err
 	}
@@ -128,13 +237,13 @@ func CommandMapb(ctx *Context, parameters []string) error {
 	ctx.LocationConfig.Previous = areas.Previous
 
 	for _, result := range areas.Results {
-		fmt.Printf("%s\n", result.Name)
+		fmt.Fprintf(ctx.out(), "%s\n", result.Name)
 	}
 
 	return nil
 }
 
-// Explores a provided area and lists Pokemon in the area
+// Explores a provided area and lists Pokemon in the area with their types
 func CommandExplore(ctx *Context, parameters []string) error {
 	if len(parameters) == 0 {
 		return fmt.Errorf("'explore' no area provided")
@@ -142,12 +251,35 @@ func CommandExplore(ctx *Context, parameters []string) error {
 	if len(parameters) > 1 {
 		return fmt.Errorf("'explore' expects only one area. try replacing ' ' with '-'")
 	}
-	area, err := ctx.Client.GetLocationArea(parameters[0])
+	name := strings.ToLower(parameters[0])
+	area, err := ctx.Client.GetLocationAreaContext(ctx.commandContext(), name)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("no area named %v", name)
+	}
 	if err != nil {
 		return err
 	}
-	for _, encounter := range area.PokemonEncounters {
-		fmt.Printf("%s\n", encounter.Pokemon.Name)
+
+	// Look up every encounter at once for its types, listing just the name
+	// of any that fail
+	names := make([]string, len(area.PokemonEncounters))
+	for i, encounter := range area.PokemonEncounters {
+		names[i] = encounter.Pokemon.Name
+	}
+	results := ctx.Client.GetPokemonBatch(ctx.commandContext(), names)
+	for i, result := range results {
+		if errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, context.DeadlineExceeded) {
+			return result.Err
+		}
+		if result.Err != nil {
+			fmt.Fprintf(ctx.out(), "%s\n", names[i])
+			continue
+		}
+		types := make([]string, 0, len(result.Value.Types))
+		for _, item := range result.Value.Types {
+			types = append(types, item.Type.Name)
+		}
+		fmt.Fprintf(ctx.out(), "%s (%s)\n", names[i], strings.Join(types, "/"))
 	}
 
 	return nil
@@ -163,8 +295,11 @@ func CommandCatch(ctx *Context, parameters []string) error {
 	}
 
 	// Grab pokemon from the Pokedex
-	key := parameters[0]
-	pokemon, err := ctx.Client.GetPokemon(key)
+	key := strings.ToLower(parameters[0])
+	pokemon, err := ctx.Client.GetPokemonContext(ctx.commandContext(), key)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("no Pokemon named %v", key)
+	}
 	if err != nil {
 		return err
 	}
@@ -180,13 +315,18 @@ func CommandCatch(ctx *Context, parameters []string) error {
 		prob = 0.9
 	}
 	// add to pokedex if caught, otherwise let use know it failed
-	fmt.Printf("Throwing a Pokeball at %v...\n", pokemon.Name)
+	fmt.Fprintf(ctx.out(), "Throwing a Pokeball at %v...\n", pokemon.Name)
 	if rand.Float64() < prob { // Success
 		ctx.Pokedex[key] = *pokemon
-		fmt.Printf("%v was caught!\n", pokemon.Name)
-		fmt.Println("You may now inspect it with the inspect command.")
+		fmt.Fprintf(ctx.out(), "%v was caught!\n", pokemon.Name)
+		fmt.Fprintln(ctx.out(), "You may now inspect it with the inspect command.")
+		if ctx.SavePath != "" {
+			if err := savefile.Save(ctx.SavePath, ctx.Pokedex); err != nil {
+				return fmt.Errorf("%v was caught but autosave failed: %v", pokemon.Name, err)
+			}
+		}
 	} else { // Failure
-		fmt.Printf("%v escaped!\n", pokemon.Name)
+		fmt.Fprintf(ctx.out(), "%v escaped!\n", pokemon.Name)
 	}
 	return nil
 }
@@ -201,23 +341,52 @@ func CommandInspect(ctx *Context, parameters []string) error {
 	}
 
 	// Grab pokemon from the Pokedex
-	key := parameters[0]
+	key := strings.ToLower(parameters[0])
 	pokemon, ok := ctx.Pokedex[key]
 	if !ok {
 		return fmt.Errorf("you have not caught that pokemon")
 	}
 
+	// Species details need the network, inspect still works without them
+	// unless the user cancelled the command
+	var species *pokeapi.PokemonSpecies
+	if pokemon.Species.Name != "" {
+		var err error
+		species, err = ctx.Client.GetPokemonSpeciesContext(ctx.commandContext(), pokemon.Species.Name)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+	}
+
 	// Output pertinent information about the Pokemon
-	fmt.Printf("Name: %v\n", pokemon.Name)
-	fmt.Printf("Height: %v\n", pokemon.Height)
-	fmt.Printf("Weight: %v\n", pokemon.Weight)
-	fmt.Printf("Stats:\n")
+	fmt.Fprintf(ctx.out(), "Name: %v\n", pokemon.Name)
+	if species != nil {
+		if genus := species.Genus(ctx.Language); genus != "" {
+			fmt.Fprintf(ctx.out(), "Genus: %v\n", genus)
+		}
+	}
+	fmt.Fprintf(ctx.out(), "Height: %v\n", pokemon.Height)
+	fmt.Fprintf(ctx.out(), "Weight: %v\n", pokemon.Weight)
+	fmt.Fprintf(ctx.out(), "Stats:\n")
 	for _, item := range pokemon.Stats {
-		fmt.Printf("  - %v: %v\n", item.Stat.Name, item.BaseStat)
+		fmt.Fprintf(ctx.out(), "  - %v: %v\n", item.Stat.Name, item.BaseStat)
 	}
-	fmt.Printf("Types:\n")
+	fmt.Fprintf(ctx.out(), "Types:\n")
 	for _, item := range pokemon.Types {
-		fmt.Printf("  - %v\n", item.Type.Name)
+		fmt.Fprintf(ctx.out(), "  - %v\n", item.Type.Name)
+	}
+	fmt.Fprintf(ctx.out(), "Abilities:\n")
+	for _, item := range pokemon.Abilities {
+		if item.IsHidden {
+			fmt.Fprintf(ctx.out(), "  - %v (hidden)\n", item.Ability.Name)
+		} else {
+			fmt.Fprintf(ctx.out(), "  - %v\n", item.Ability.Name)
+		}
+	}
+	if species != nil {
+		if entry := species.FlavorText(ctx.Language); entry != "" {
+			fmt.Fprintf(ctx.out(), "Pokedex entry:\n  %v\n", entry)
+		}
 	}
 
 	return nil
@@ -228,34 +397,440 @@ func CommandPokedex(ctx *Context, parameters []string) error {
 	if len(parameters) > 0 {
 		return fmt.Errorf("'pokedex' expected no parameters, got %v", parameters)
 	}
-	fmt.Println("Your Pokedex:")
+	fmt.Fprintln(ctx.out(), "Your Pokedex:")
 	for _, pokemon := range ctx.Pokedex {
-		fmt.Printf("  - %v\n", pokemon.Name)
+		fmt.Fprintf(ctx.out(), "  - %v\n", pokemon.Name)
+	}
+	return nil
+}
+
+// Prints the evolution chain of a Pokemon as a tree
+func CommandEvolutions(ctx *Context, parameters []string) error {
+	if len(parameters) == 0 {
+		return fmt.Errorf("'evolutions' no pokemon provided")
+	}
+	if len(parameters) > 1 {
+		return fmt.Errorf("'evolutions' expects only one pokemon. try replacing ' ' with '-'")
+	}
+
+	// Caught Pokemon know their species, otherwise assume the name is the species
+	name := strings.ToLower(parameters[0])
+	species := name
+	if pokemon, ok := ctx.Pokedex[name]; ok && pokemon.Species.Name != "" {
+		species = pokemon.Species.Name
+	}
+
+	chain, err := ctx.Client.GetSpeciesEvolutionChainContext(ctx.commandContext(), species)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("no Pokemon species named %v", species)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(ctx.out(), chain.Chain.Species.Name)
+	printEvolutions(ctx.out(), chain.Chain, "")
+	return nil
+}
+
+// Prints the links a species evolves into below it, drawing tree branches with prefix
+func printEvolutions(w io.Writer, link pokeapi.ChainLink, prefix string) {
+	for i, next := range link.EvolvesTo {
+		branch, indent := "├── ", "│   "
+		if i == len(link.EvolvesTo)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		conditions := make([]string, 0, len(next.EvolutionDetails))
+		for _, detail := range next.EvolutionDetails {
+			conditions = append(conditions, detail.String())
+		}
+		line := prefix + branch + next.Species.Name
+		if len(conditions) > 0 {
+			line += " (" + strings.Join(conditions, " or ") + ")"
+		}
+		fmt.Fprintln(w, line)
+
+		printEvolutions(w, next, prefix+indent)
+	}
+}
+
+// Lists how much damage each attacking type deals to a Pokemon
+func CommandWeakness(ctx *Context, parameters []string) error {
+	if len(parameters) == 0 {
+		return fmt.Errorf("'weakness' no pokemon provided")
+	}
+	if len(parameters) > 1 {
+		return fmt.Errorf("'weakness' expects only one pokemon. try replacing ' ' with '-'")
+	}
+
+	pokemon, err := lookupPokemon(ctx, parameters[0])
+	if err != nil {
+		return err
+	}
+
+	types := make([]string, 0, len(pokemon.Types))
+	for _, item := range pokemon.Types {
+		types = append(types, item.Type.Name)
+	}
+	chart, err := ctx.Client.GetTypeChartContext(ctx.commandContext(), types...)
+	if err != nil {
+		return err
+	}
+
+	// Group attacking types by multiplier, strongest first
+	groups := map[float64][]string{}
+	for _, matchup := range chart.Matchups(types...) {
+		groups[matchup.Multiplier] = append(groups[matchup.Multiplier], matchup.Type)
+	}
+	fmt.Fprintf(ctx.out(), "Matchups for %v (%v):\n", pokemon.Name, strings.Join(types, "/"))
+	for _, multiplier := range []float64{4, 2, 0.5, 0.25, 0} {
+		if names, ok := groups[multiplier]; ok {
+			fmt.Fprintf(ctx.out(), "  %vx: %v\n", multiplier, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// Prints an ability's effect and every Pokemon that can have it
+func CommandAbility(ctx *Context, parameters []string) error {
+	if len(parameters) == 0 {
+		return fmt.Errorf("'ability' no ability provided")
+	}
+	if len(parameters) > 1 {
+		return fmt.Errorf("'ability' expects only one ability. try replacing ' ' with '-'")
+	}
+
+	name := strings.ToLower(parameters[0])
+	ability, err := ctx.Client.GetAbilityContext(ctx.commandContext(), name)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("no ability named %v", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctx.out(), "Name: %v\n", ability.Name)
+	if effect := ability.Effect(ctx.Language); effect != "" {
+		fmt.Fprintf(ctx.out(), "Effect:\n  %v\n", effect)
+	}
+	fmt.Fprintf(ctx.out(), "Pokemon:\n")
+	for _, item := range ability.Pokemon {
+		if item.IsHidden {
+			fmt.Fprintf(ctx.out(), "  - %v (hidden)\n", item.Pokemon.Name)
+		} else {
+			fmt.Fprintf(ctx.out(), "  - %v\n", item.Pokemon.Name)
+		}
 	}
 	return nil
 }
 
+// Order learn methods are listed in, unknown methods sort last
+var learnMethodOrder = map[string]int{
+	"level-up": 0,
+	"machine":  1,
+	"egg":      2,
+	"tutor":    3,
+}
+
+// Prints a table of the moves a Pokemon learns
+func CommandMoves(ctx *Context, parameters []string) error {
+	args, flags, err := parseFlags(parameters, "version-group", "method")
+	if err != nil {
+		return fmt.Errorf("'moves' %v", err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("'moves' no pokemon provided")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("'moves' expects only one pokemon. try replacing ' ' with '-'")
+	}
+
+	pokemon, err := lookupPokemon(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	learnset := pokemon.Learnset(strings.ToLower(flags["version-group"]), strings.ToLower(flags["method"]))
+	if len(learnset) == 0 {
+		return fmt.Errorf("%v learns no moves matching those filters", pokemon.Name)
+	}
+
+	sort.Slice(learnset, func(i, j int) bool {
+		a, b := learnset[i], learnset[j]
+		if orderA, orderB := methodOrder(a.Method), methodOrder(b.Method); orderA != orderB {
+			return orderA < orderB
+		}
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		return a.Move < b.Move
+	})
+
+	fmt.Fprintf(ctx.out(), "Moves for %v in %v:\n", pokemon.Name, learnset[0].VersionGroup)
+	names := make([]string, len(learnset))
+	for i, entry := range learnset {
+		names[i] = entry.Move
+	}
+	moves := ctx.Client.GetMoveBatch(ctx.commandContext(), names)
+
+	table := tabwriter.NewWriter(ctx.out(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "METHOD\tLEVEL\tMOVE\tTYPE\tCLASS\tPOWER\tACC\tPP\tEFFECT")
+	for i, entry := range learnset {
+		if moves[i].Err != nil {
+			table.Flush()
+			return moves[i].Err
+		}
+		move := moves[i].Value
+		level := "-"
+		if entry.Method == "level-up" {
+			level = strconv.Itoa(entry.Level)
+		}
+		fmt.Fprintf(table, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			entry.Method, level, move.Name, move.Type.Name, move.DamageClass.Name,
+			optionalInt(move.Power), optionalInt(move.Accuracy), optionalInt(move.PP), move.ShortEffect(ctx.Language))
+	}
+	return table.Flush()
+}
+
+func methodOrder(method string) int {
+	if order, ok := learnMethodOrder[method]; ok {
+		return order
+	}
+	return len(learnMethodOrder)
+}
+
+// Formats a nullable PokeAPI number, "-" when null
+func optionalInt(n *int) string {
+	if n == nil {
+		return "-"
+	}
+	return strconv.Itoa(*n)
+}
+
+// Splits parameters into positional arguments and the named --flags, which
+// take a value as "--flag value" or "--flag=value"
+func parseFlags(parameters []string, names ...string) ([]string, map[string]string, error) {
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+
+	var args []string
+	flags := make(map[string]string)
+	for i := 0; i < len(parameters); i++ {
+		param := parameters[i]
+		if !strings.HasPrefix(param, "--") {
+			args = append(args, param)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(param, "--"), "=")
+		if !known[name] {
+			return nil, nil, fmt.Errorf("unknown flag --%v", name)
+		}
+		if !hasValue {
+			if i+1 >= len(parameters) {
+				return nil, nil, fmt.Errorf("flag --%v needs a value", name)
+			}
+			i++
+			value = parameters[i]
+		}
+		flags[name] = value
+	}
+	return args, flags, nil
+}
+
+// Gets a Pokemon from the Pokedex if caught, otherwise from PokeAPI
+func lookupPokemon(ctx *Context, name string) (*pokeapi.Pokemon, error) {
+	name = strings.ToLower(name)
+	if pokemon, ok := ctx.Pokedex[name]; ok {
+		return &pokemon, nil
+	}
+	pokemon, err := ctx.Client.GetPokemonContext(ctx.commandContext(), name)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return nil, fmt.Errorf("no Pokemon named %v", name)
+	}
+	return pokemon, err
+}
+
+// Reports or clears the PokeAPI response cache
+func CommandCache(ctx *Context, parameters []string) error {
+	if len(parameters) != 1 {
+		return fmt.Errorf("'cache' expects one of 'stats' or 'clear'")
+	}
+
+	switch strings.ToLower(parameters[0]) {
+	case "stats":
+		stores, err := ctx.Client.CacheStats()
+		if err != nil {
+			return err
+		}
+		for _, stats := range stores {
+			fmt.Fprintf(ctx.out(), "%v: %v entries, %v bytes", stats.Name, stats.Entries, stats.Bytes)
+			if stats.Location != "" {
+				fmt.Fprintf(ctx.out(), " in %v", stats.Location)
+			}
+			fmt.Fprintln(ctx.out())
+		}
+	case "clear":
+		if err := ctx.Client.ClearCache(); err != nil {
+			return err
+		}
+		fmt.Fprintln(ctx.out(), "Cache cleared")
+	default:
+		return fmt.Errorf("'cache' unknown subcommand '%v', expected 'stats' or 'clear'", parameters[0])
+	}
+	return nil
+}
+
+// Crawls resource lists into a bundle directory for --offline
+func CommandBundle(ctx *Context, parameters []string) error {
+	args, flags, err := parseFlags(parameters, "limit")
+	if err != nil {
+		return fmt.Errorf("'bundle' %v", err)
+	}
+	if len(args) == 0 || strings.ToLower(args[0]) != "build" {
+		return fmt.Errorf("'bundle' expects 'build <dir> <resource>...'")
+	}
+	if len(args) < 3 {
+		return fmt.Errorf("'bundle build' expects a directory and at least one resource, e.g. 'bundle build ./bundle pokemon'")
+	}
+	if ctx.Client.Offline() {
+		return fmt.Errorf("'bundle build' needs PokeAPI, restart without --offline")
+	}
+
+	limit := 0
+	if value, ok := flags["limit"]; ok {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return fmt.Errorf("'bundle' --limit must be a positive number, got %v", value)
+		}
+	}
+
+	dir := args[1]
+	for _, resource := range args[2:] {
+		resource = strings.ToLower(resource)
+		n, err := ctx.Client.BuildBundle(ctx.commandContext(), dir, resource, limit)
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			return fmt.Errorf("no resource named %v", resource)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(ctx.out(), "Bundled %v %v into %v\n", n, resource, dir)
+	}
+	return nil
+}
+
+// Resolves the save path from the optional parameter, defaulting to ctx.SavePath
+func savePath(ctx *Context, command string, parameters []string) (string, error) {
+	if len(parameters) > 1 {
+		return "", fmt.Errorf("'%v' expects at most one path", command)
+	}
+	if len(parameters) == 1 {
+		return parameters[0], nil
+	}
+	if ctx.SavePath == "" {
+		return "", fmt.Errorf("'%v' no save path configured", command)
+	}
+	return ctx.SavePath, nil
+}
+
+// Saves the users Pokedex to disk
+func CommandSave(ctx *Context, parameters []string) error {
+	path, err := savePath(ctx, "save", parameters)
+	if err != nil {
+		return err
+	}
+	if err := savefile.Save(path, ctx.Pokedex); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.out(), "Saved %v Pokemon to %v\n", len(ctx.Pokedex), path)
+	return nil
+}
+
+// Replaces the users Pokedex with one loaded from disk
+func CommandLoad(ctx *Context, parameters []string) error {
+	path, err := savePath(ctx, "load", parameters)
+	if err != nil {
+		return err
+	}
+	pokedex, err := savefile.Load(path)
+	if err != nil {
+		return err
+	}
+	// Autosave back to the loaded file so the previous save is not overwritten
+	ctx.Pokedex = pokedex
+	ctx.SavePath = path
+	fmt.Fprintf(ctx.out(), "Loaded %v Pokemon from %v, autosaving there\n", len(ctx.Pokedex), path)
+	return nil
+}
+
 func strPtr(s string) *string {
 	return &s
 }
 
-func Start() {
+// Runs the REPL on stdin and stdout against the provided PokeAPI client with
+// the Pokedex from the last session, until exit. Pokedex entries are shown in
+// language when PokeAPI has them, English otherwise. Ctrl-C cancels the
+// running command instead of quitting.
+func Start(client PokeAPI, language string) {
 	ctx := Context{
-		Client: pokeapi.NewClient(),
-		LocationConfig: &pokeapi.Config{
-			Next:     strPtr(pokeapi.LocationAreaURL),
+		Client:   client,
+		Language: language,
+		LocationConfig: &PageConfig{
+			Next:     strPtr(client.LocationAreaURL()),
 			Previous: nil,
 		},
 		Pokedex: make(map[string]pokeapi.Pokemon),
 	}
-	userInputScanner := bufio.NewScanner(os.Stdin)
+
+	// Restore the Pokedex from the last session
+	path, err := savefile.DefaultPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error finding save file, autosave disabled: %v\n", err)
+	} else if pokedex, err := savefile.Load(path); err != nil {
+		// Leave autosave off so a bad save file is never overwritten
+		fmt.Fprintf(os.Stderr, "error loading save file, autosave disabled: %v\n", err)
+	} else {
+		ctx.SavePath = path
+		ctx.Pokedex = pokedex
+	}
+
+	ctx.handleInterrupts = true
+	if err := Run(&ctx, os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "error reading input: %v\n", err)
+	}
+}
+
+// Read commands from in and run them until 'exit' or the end of in. Output
+// goes to out and failed commands are reported on errOut, nil writers mean
+// stdout and stderr. Run leaves SIGINT to the embedding program, only Start
+// turns Ctrl-C into cancellation. Returns an error only when reading in fails.
+func Run(ctx *Context, in io.Reader, out, errOut io.Writer) error {
+	ctx.Out = out
+	ctx.ErrOut = errOut
+	if ctx.LocationConfig == nil {
+		ctx.LocationConfig = &PageConfig{Next: strPtr(ctx.Client.LocationAreaURL())}
+	}
+	if ctx.Pokedex == nil {
+		ctx.Pokedex = make(map[string]pokeapi.Pokemon)
+	}
+
+	var interrupts *interruptHandler
+	if ctx.handleInterrupts {
+		interrupts = newInterruptHandler(ctx.out())
+		defer interrupts.stop()
+	}
+
+	userInputScanner := bufio.NewScanner(in)
 	for {
-		fmt.Print("Pokedex > ")
+		fmt.Fprint(ctx.out(), "Pokedex > ")
 
-		// Block until a user gives input
-		success := userInputScanner.Scan()
-		if !success {
-			continue
+		// Block until a user gives input, stop at the end of it
+		if !userInputScanner.Scan() {
+			fmt.Fprintln(ctx.out())
+			return userInputScanner.Err()
 		}
 
 		// Get user input and parse into tokens
@@ -269,13 +844,28 @@ func Start() {
 		command := tokens[0]
 		cli, ok := GetCommandRegistry()[command]
 		if !ok {
-			fmt.Printf("error command '%s' not in registry\n", command)
+			fmt.Fprintf(ctx.errOut(), "error command '%s' not in registry\n", command)
 			continue
 		}
 
-		// Run the command with the current context
-		if err := cli.Callback(&ctx, tokens[1:]); err != nil {
-			fmt.Printf("error command failed: %v\n", err)
+		// Run the command with the current context, Ctrl-C cancels it
+		var err error
+		if interrupts != nil {
+			cmdCtx, cancel := interrupts.begin()
+			ctx.cmdCtx = cmdCtx
+			err = cli.Callback(ctx, tokens[1:])
+			interrupts.end()
+			cancel()
+			ctx.cmdCtx = nil
+		} else {
+			err = cli.Callback(ctx, tokens[1:])
+		}
+
+		if errors.Is(err, errExit) {
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintf(ctx.errOut(), "error command failed: %v\n", friendlyError(err))
 		}
 	}
 }
@@ -2,13 +2,19 @@ package repl
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/evanwiseman/pokedexcli/internal/pokeapi"
+	"github.com/evanwiseman/pokedexcli/internal/pokeapi/pokeapitest"
+	"github.com/evanwiseman/pokedexcli/internal/savefile"
 )
 
 type funcStep struct {
@@ -28,7 +34,7 @@ func TestCleanInput(t *testing.T) {
 		},
 		{
 			input:    "  HELLO  WORLD  ",
-			expected: []string{"hello", "world"},
+			expected: []string{"hello", "WORLD"},
 		},
 		{
 			input:    "hello world",
@@ -36,7 +42,7 @@ func TestCleanInput(t *testing.T) {
 		},
 		{
 			input:    "HELLO WORLD",
-			expected: []string{"hello", "world"},
+			expected: []string{"hello", "WORLD"},
 		},
 		{
 			input:    "thisis/text and a field",
@@ -44,7 +50,11 @@ func TestCleanInput(t *testing.T) {
 		},
 		{
 			input:    "hElLo WoRlD =,./[]",
-			expected: []string{"hello", "world", "=,./[]"},
+			expected: []string{"hello", "WoRlD", "=,./[]"},
+		},
+		{
+			input:    "SAVE ~/Dex/MyDex.json",
+			expected: []string{"save", "~/Dex/MyDex.json"},
 		},
 	}
 
@@ -67,51 +77,72 @@ func TestCleanInput(t *testing.T) {
 }
 
 func TestCommandExit(t *testing.T) {
-	if os.Getenv("TEST_EXIT") == "1" {
-		if err := CommandExit(nil, nil); err != nil {
-			t.Fatalf("unexpected error: %v", err)
-			t.Fail()
-		}
-		return
-	}
+	var buf bytes.Buffer
+	ctx := Context{Out: &buf}
 
-	cmd := exec.Command(os.Args[0], "-test.run=TestCommandExit")
-	cmd.Env = append(os.Environ(), "TEST_EXIT=1")
+	err := CommandExit(&ctx, nil)
+	if !errors.Is(err, errExit) {
+		t.Fatalf("expected errExit, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "Closing the Pokedex... Goodbye!") {
+		t.Errorf("expected exit message, got: %q", buf.String())
+	}
+}
 
-	output, err := cmd.CombinedOutput()
+func TestCommandHelp(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := Context{Out: &buf}
 
-	// check exit code
-	if exitErr, ok := err.(*exec.ExitError); ok {
-		if exitErr.ExitCode() != 0 {
-			t.Fatalf("expected exit code 0, got %d", exitErr.ExitCode())
-		}
-	} else if err != nil {
+	if err := CommandHelp(&ctx, nil); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	// check printed output
-	if !strings.Contains(string(output), "Closing the Pokedex... Goodbye!") {
-		t.Errorf("expected exit message, got: %q", string(output))
+	if !strings.Contains(buf.String(), "Usage") {
+		t.Errorf("expected 'Usage' in output, got: %q", buf.String())
 	}
 }
 
-func TestCommandHelp(t *testing.T) {
-	if os.Getenv("TEST_HELP") == "1" {
-		if err := CommandHelp(nil, nil); err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		return
+func TestRun(t *testing.T) {
+	server := pokeapitest.NewServer(t)
+	cases := []struct {
+		input          string
+		expectOut      []string
+		expectErrOut   []string
+		expectNotInOut string
+	}{
+		{
+			input:     "map\nexplore canalave-city-area\n",
+			expectOut: []string{"Pokedex > canalave-city-area", "staryu", "Pokedex > \n"},
+		},
+		{
+			input:          "help\nexit\nmap\n",
+			expectOut:      []string{"Usage", "Goodbye!"},
+			expectNotInOut: "canalave-city-area",
+		},
+		{
+			input:        "bogus\nmapb\nexplore nowhere\n",
+			expectErrOut: []string{"'bogus' not in registry", "you're on the first page", "no area named nowhere"},
+		},
 	}
 
-	cmd := exec.Command(os.Args[0], "-test.run=TestCommandHelp")
-	cmd.Env = append(os.Environ(), "TEST_HELP=1")
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if !strings.Contains(string(output), "Usage") {
-		t.Errorf("expected 'Usage' in output, got: %q", string(output))
+	for _, c := range cases {
+		ctx := Context{Client: server.Client(t)}
+		var out, errOut bytes.Buffer
+		if err := Run(&ctx, strings.NewReader(c.input), &out, &errOut); err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		for _, expect := range c.expectOut {
+			if !strings.Contains(out.String(), expect) {
+				t.Errorf("expected output to contain %q, got %q", expect, out.String())
+			}
+		}
+		for _, expect := range c.expectErrOut {
+			if !strings.Contains(errOut.String(), expect) {
+				t.Errorf("expected error output to contain %q, got %q", expect, errOut.String())
+			}
+		}
+		if c.expectNotInOut != "" && strings.Contains(out.String(), c.expectNotInOut) {
+			t.Errorf("expected output not to contain %q, got %q", c.expectNotInOut, out.String())
+		}
 	}
 }
 
@@ -144,26 +175,22 @@ func TestCommandMapMapb(t *testing.T) {
 			},
 		},
 	}
+	server := pokeapitest.NewServer(t)
 	for _, c := range cases {
+		client := server.Client(t)
 		ctx := Context{
-			Client: pokeapi.NewClient(),
-			LocationConfig: &pokeapi.Config{
-				Next:     strPtr(pokeapi.LocationAreaURL),
+			Client: client,
+			LocationConfig: &PageConfig{
+				Next:     strPtr(client.LocationAreaURL()),
 				Previous: nil,
 			},
 		}
 		for _, step := range c.steps {
-			r, w, _ := os.Pipe()
-			old := os.Stdout
-			os.Stdout = w
+			var buf bytes.Buffer
+			ctx.Out = &buf
 
 			err := step.fn(&ctx, nil)
 
-			w.Close()
-			var buf bytes.Buffer
-			io.Copy(&buf, r)
-			os.Stdout = old
-
 			if step.expectError && err == nil {
 				t.Errorf("expected error but got nil")
 			} else if !step.expectError && err != nil {
@@ -187,7 +214,7 @@ func TestCommandExplore(t *testing.T) {
 					fn: func(ctx *Context, _ []string) error {
 						return CommandExplore(ctx, []string{"canalave-city-area"})
 					},
-					expectContains: "staryu", // expect one Pokémon known in the area
+					expectContains: "staryu (water)\n", // expect one Pokémon known in the area
 					expectError:    false,
 				},
 			},
@@ -216,23 +243,18 @@ func TestCommandExplore(t *testing.T) {
 		},
 	}
 
+	server := pokeapitest.NewServer(t)
 	for _, c := range cases {
 		ctx := Context{
-			Client: pokeapi.NewClient(),
+			Client: server.Client(t),
 		}
 
 		for _, step := range c.steps {
-			r, w, _ := os.Pipe()
-			old := os.Stdout
-			os.Stdout = w
+			var buf bytes.Buffer
+			ctx.Out = &buf
 
 			err := step.fn(&ctx, nil)
 
-			w.Close()
-			var buf bytes.Buffer
-			io.Copy(&buf, r)
-			os.Stdout = old
-
 			if step.expectError && err == nil {
 				t.Errorf("expected error but got nil")
 			} else if !step.expectError && err != nil {
@@ -246,10 +268,30 @@ func TestCommandExplore(t *testing.T) {
 	}
 }
 
+func TestCommandExploreTypes(t *testing.T) {
+	client := newFakeAPI()
+	client.areas = []pokeapi.LocationArea{decode[pokeapi.LocationArea](t, `{"id":1,"name":"canalave-city-area",
+		"pokemon_encounters":[{"pokemon":{"name":"tentacool"}},{"pokemon":{"name":"missingno"}},{"pokemon":{"name":"staryu"}}]}`)}
+	client.pokemon["tentacool"] = decode[pokeapi.Pokemon](t, `{"name":"tentacool","types":[{"slot":1,"type":{"name":"water"}},{"slot":2,"type":{"name":"poison"}}]}`)
+	client.pokemon["staryu"] = decode[pokeapi.Pokemon](t, `{"name":"staryu","types":[{"slot":1,"type":{"name":"water"}}]}`)
+
+	var buf bytes.Buffer
+	ctx := Context{Client: client, Out: &buf}
+	if err := CommandExplore(&ctx, []string{"canalave-city-area"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Encounters keep their order, and one that cannot be looked up is listed by name
+	expected := "tentacool (water/poison)\nmissingno\nstaryu (water)\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
 func TestCommandCatch(t *testing.T) {
+	server := pokeapitest.NewServer(t)
 	ctx := Context{
 		Pokedex: make(map[string]pokeapi.Pokemon),
-		Client:  pokeapi.NewClient(),
+		Client:  server.Client(t),
 	}
 
 	cases := []struct {
@@ -275,16 +317,525 @@ func TestCommandCatch(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		r, w, _ := os.Pipe()
-		old := os.Stdout
-		os.Stdout = w
+		var buf bytes.Buffer
+		ctx.Out = &buf
 
 		err := CommandCatch(&ctx, c.parameters)
 
-		w.Close()
+		if c.expectError && err == nil {
+			t.Errorf("expected error but got nil")
+		} else if !c.expectError && err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if c.expectContains != "" && !strings.Contains(buf.String(), c.expectContains) {
+			t.Errorf("expected output to contain %q, got %q", c.expectContains, buf.String())
+		}
+	}
+}
+
+func TestCommandSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	ctx := Context{
+		Pokedex: map[string]pokeapi.Pokemon{
+			"pikachu": {Name: "pikachu"},
+		},
+		SavePath: path,
+	}
+
+	cases := []struct {
+		fn          func(ctx *Context, parameters []string) error
+		parameters  []string
+		expectError bool
+	}{
+		{fn: CommandSave, parameters: nil, expectError: false},
+		{fn: CommandLoad, parameters: nil, expectError: false},
+		{fn: CommandLoad, parameters: []string{path}, expectError: false},
+		{fn: CommandSave, parameters: []string{"a", "b"}, expectError: true},
+	}
+
+	for _, c := range cases {
+		ctx.Out = io.Discard
+
+		err := c.fn(&ctx, c.parameters)
+
+		if c.expectError && err == nil {
+			t.Errorf("expected error but got nil")
+		} else if !c.expectError && err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if _, ok := ctx.Pokedex["pikachu"]; !ok {
+		t.Errorf("expected pikachu after load, got %v", ctx.Pokedex)
+	}
+}
+
+func TestCommandCache(t *testing.T) {
+	client := newFakeAPI()
+	client.cached = 3
+	ctx := Context{
+		Client: client,
+	}
+
+	cases := []struct {
+		parameters     []string
+		expectContains string
+		expectError    bool
+	}{
+		{parameters: []string{"stats"}, expectContains: "fake: 3 entries", expectError: false},
+		{parameters: []string{"clear"}, expectContains: "Cache cleared", expectError: false},
+		{parameters: []string{"stats"}, expectContains: "fake: 0 entries", expectError: false},
+		{parameters: []string{}, expectContains: "", expectError: true},
+		{parameters: []string{"purge"}, expectContains: "", expectError: true},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		ctx.Out = &buf
+
+		err := CommandCache(&ctx, c.parameters)
+
+		if c.expectError && err == nil {
+			t.Errorf("expected error but got nil")
+		} else if !c.expectError && err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if c.expectContains != "" && !strings.Contains(buf.String(), c.expectContains) {
+			t.Errorf("expected output to contain %q, got %q", c.expectContains, buf.String())
+		}
+	}
+}
+
+func TestCommandEvolutions(t *testing.T) {
+	client := newFakeAPI()
+	client.chains["charmander"] = decode[pokeapi.EvolutionChain](t, `{"id":2,"chain":{"species":{"name":"charmander"},"evolves_to":[
+		{"species":{"name":"charmeleon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":16}],"evolves_to":[
+			{"species":{"name":"charizard"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":36}],"evolves_to":[]}
+		]}
+	]}}`)
+	ctx := Context{
+		Client: client,
+	}
+
+	cases := []struct {
+		parameters     []string
+		expectContains string
+		expectError    bool
+	}{
+		{
+			parameters:     []string{"charmander"},
+			expectContains: "charmander\n└── charmeleon (level-up, level 16)\n    └── charizard (level-up, level 36)\n",
+			expectError:    false,
+		},
+		{parameters: []string{}, expectContains: "", expectError: true},
+		{parameters: []string{"missingno"}, expectContains: "", expectError: true},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		ctx.Out = &buf
+
+		err := CommandEvolutions(&ctx, c.parameters)
+
+		if c.expectError && err == nil {
+			t.Errorf("expected error but got nil")
+		} else if !c.expectError && err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if c.expectContains != "" && !strings.Contains(buf.String(), c.expectContains) {
+			t.Errorf("expected output to contain %q, got %q", c.expectContains, buf.String())
+		}
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	cases := []struct {
+		parameters  []string
+		expectArgs  []string
+		expectFlags map[string]string
+		expectError bool
+	}{
+		{
+			parameters:  []string{"pikachu"},
+			expectArgs:  []string{"pikachu"},
+			expectFlags: map[string]string{},
+		},
+		{
+			parameters:  []string{"pikachu", "--method", "machine", "--version-group=red-blue"},
+			expectArgs:  []string{"pikachu"},
+			expectFlags: map[string]string{"method": "machine", "version-group": "red-blue"},
+		},
+		{
+			parameters:  []string{"--method", "egg", "pikachu"},
+			expectArgs:  []string{"pikachu"},
+			expectFlags: map[string]string{"method": "egg"},
+		},
+		{parameters: []string{"pikachu", "--method"}, expectError: true},
+		{parameters: []string{"pikachu", "--shiny", "yes"}, expectError: true},
+	}
+
+	for _, c := range cases {
+		args, flags, err := parseFlags(c.parameters, "version-group", "method")
+		if c.expectError {
+			if err == nil {
+				t.Errorf("parseFlags(%v) expected error but got nil", c.parameters)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseFlags(%v) unexpected error: %v", c.parameters, err)
+			continue
+		}
+		if strings.Join(args, " ") != strings.Join(c.expectArgs, " ") {
+			t.Errorf("parseFlags(%v) args = %v, expected %v", c.parameters, args, c.expectArgs)
+		}
+		if len(flags) != len(c.expectFlags) {
+			t.Errorf("parseFlags(%v) flags = %v, expected %v", c.parameters, flags, c.expectFlags)
+		}
+		for name, value := range c.expectFlags {
+			if flags[name] != value {
+				t.Errorf("parseFlags(%v) --%v = %q, expected %q", c.parameters, name, flags[name], value)
+			}
+		}
+	}
+}
+
+func TestCommandBundle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/type/":
+			w.Write([]byte(`{"count":1,"results":[{"name":"fire","url":"http://` + r.Host + `/type/10/"}]}`))
+		case "/type/10/":
+			w.Write([]byte(`{"id":10,"name":"fire"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+	defer client.Close()
+	ctx := Context{
+		Client: client,
+	}
+	dir := t.TempDir()
+
+	cases := []struct {
+		parameters     []string
+		expectContains string
+		expectError    bool
+	}{
+		{parameters: []string{"build", dir, "type"}, expectContains: "Bundled 1 type into " + dir, expectError: false},
+		{parameters: []string{"build", dir, "type", "--limit", "0"}, expectContains: "", expectError: true},
+		{parameters: []string{"build", dir}, expectContains: "", expectError: true},
+		{parameters: []string{"pack", dir, "type"}, expectContains: "", expectError: true},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		ctx.Out = &buf
+
+		err := CommandBundle(&ctx, c.parameters)
+
+		if c.expectError && err == nil {
+			t.Errorf("expected error but got nil")
+		} else if !c.expectError && err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if c.expectContains != "" && !strings.Contains(buf.String(), c.expectContains) {
+			t.Errorf("expected output to contain %q, got %q", c.expectContains, buf.String())
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "type", "fire", "index.json")); err != nil {
+		t.Errorf("expected fire in bundle: %v", err)
+	}
+
+	// The directory keeps its case when typed into the REPL
+	mixedDir := filepath.Join(t.TempDir(), "MyBundle")
+	var out, errOut bytes.Buffer
+	if err := Run(&ctx, strings.NewReader("bundle build "+mixedDir+" TYPE\n"), &out, &errOut); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(mixedDir, "type", "fire", "index.json")); err != nil {
+		t.Errorf("expected fire in %v: %v, errors %q", mixedDir, err, errOut.String())
+	}
+}
+
+func TestCommandWeakness(t *testing.T) {
+	client := newFakeAPI()
+	client.pokemon["charizard"] = decode[pokeapi.Pokemon](t, `{"name":"charizard","types":[
+		{"slot":1,"type":{"name":"fire"}},{"slot":2,"type":{"name":"flying"}}
+	]}`)
+	client.types["fire"] = decode[pokeapi.Type](t, `{"name":"fire","damage_relations":{
+		"double_damage_from":[{"name":"water"},{"name":"rock"},{"name":"ground"}],
+		"half_damage_from":[{"name":"fire"},{"name":"grass"},{"name":"bug"}]
+	}}`)
+	client.types["flying"] = decode[pokeapi.Type](t, `{"name":"flying","damage_relations":{
+		"double_damage_from":[{"name":"rock"},{"name":"electric"}],
+		"half_damage_from":[{"name":"grass"},{"name":"bug"}],
+		"no_damage_from":[{"name":"ground"}]
+	}}`)
+	ctx := Context{
+		Client: client,
+	}
+
+	cases := []struct {
+		parameters     []string
+		expectContains string
+		expectError    bool
+	}{
+		{parameters: []string{"charizard"}, expectContains: "4x: rock\n  2x: water, electric\n", expectError: false},
+		{parameters: []string{"charizard"}, expectContains: "0.25x: bug, grass\n  0x: ground\n", expectError: false},
+		{parameters: []string{}, expectContains: "", expectError: true},
+		{parameters: []string{"missingno"}, expectContains: "", expectError: true},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		ctx.Out = &buf
+
+		err := CommandWeakness(&ctx, c.parameters)
+
+		if c.expectError && err == nil {
+			t.Errorf("expected error but got nil")
+		} else if !c.expectError && err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if c.expectContains != "" && !strings.Contains(buf.String(), c.expectContains) {
+			t.Errorf("expected output to contain %q, got %q", c.expectContains, buf.String())
+		}
+	}
+
+	// Errors from the API reach the user through friendlyError
+	client.err = &pokeapi.StatusError{StatusCode: 429}
+	err := friendlyError(CommandWeakness(&ctx, []string{"charizard"}))
+	if err == nil || !strings.Contains(err.Error(), "rate limiting") {
+		t.Errorf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestRunKeepsArgumentCase(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "MyDex.json")
+	client := newFakeAPI()
+	client.pokemon["pikachu"] = pokeapi.Pokemon{Name: "pikachu"}
+	ctx := Context{
+		Client: client,
+		Pokedex: map[string]pokeapi.Pokemon{
+			"pikachu": {Name: "pikachu"},
+		},
+	}
+
+	var out, errOut bytes.Buffer
+	input := "Save " + path + "\nWEAKNESS Pikachu\n"
+	if err := Run(&ctx, strings.NewReader(input), &out, &errOut); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("unexpected error output: %q", errOut.String())
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected save at %v: %v", path, err)
+	}
+	if !strings.Contains(out.String(), "Matchups for pikachu") {
+		t.Errorf("expected names to be lowercased, got %q", out.String())
+	}
+}
+
+func TestCommandLoadSetsSavePath(t *testing.T) {
+	dir := t.TempDir()
+	defaultPath := filepath.Join(dir, "pokedex.json")
+	otherPath := filepath.Join(dir, "other.json")
+	if err := savefile.Save(defaultPath, map[string]pokeapi.Pokemon{"bulbasaur": {Name: "bulbasaur"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := savefile.Save(otherPath, map[string]pokeapi.Pokemon{"pikachu": {Name: "pikachu"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := Context{SavePath: defaultPath, Out: io.Discard}
+	if err := CommandLoad(&ctx, []string{otherPath}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ctx.SavePath != otherPath {
+		t.Errorf("expected autosave to %v, got %v", otherPath, ctx.SavePath)
+	}
+
+	// Saving the loaded dex leaves the default save alone
+	if err := CommandSave(&ctx, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pokedex, err := savefile.Load(defaultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pokedex["bulbasaur"]; !ok || len(pokedex) != 1 {
+		t.Errorf("expected default save to be untouched, got %v", pokedex)
+	}
+}
+
+func TestCommandMoves(t *testing.T) {
+	client := newFakeAPI()
+	client.pokemon["pikachu"] = decode[pokeapi.Pokemon](t, `{"name":"pikachu","moves":[
+		{"move":{"name":"thunder-shock"},"version_group_details":[
+			{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"red-blue","url":"https://pokeapi.co/api/v2/version-group/1/"}},
+			{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}
+		]},
+		{"move":{"name":"thunderbolt"},"version_group_details":[
+			{"level_learned_at":0,"move_learn_method":{"name":"machine"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}
+		]},
+		{"move":{"name":"quick-attack"},"version_group_details":[
+			{"level_learned_at":6,"move_learn_method":{"name":"level-up"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}
+		]},
+		{"move":{"name":"volt-tackle"},"version_group_details":[
+			{"level_learned_at":0,"move_learn_method":{"name":"egg"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}
+		]},
+		{"move":{"name":"growl"},"version_group_details":[
+			{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}
+		]}
+	]}`)
+	for _, move := range []string{
+		`{"name":"thunder-shock","type":{"name":"electric"},"damage_class":{"name":"special"},"power":40,"accuracy":100,"pp":30,"effect_chance":10,
+			"effect_entries":[{"short_effect":"Has a $effect_chance% chance to paralyze the target.","language":{"name":"en"}}]}`,
+		`{"name":"thunderbolt","type":{"name":"electric"},"damage_class":{"name":"special"},"power":90,"accuracy":100,"pp":15,
+			"effect_entries":[{"short_effect":"May paralyze the target.","language":{"name":"en"}}]}`,
+		`{"name":"quick-attack","type":{"name":"normal"},"damage_class":{"name":"physical"},"power":40,"accuracy":100,"pp":30,
+			"effect_entries":[{"short_effect":"Usually goes first.","language":{"name":"en"}}]}`,
+		`{"name":"volt-tackle","type":{"name":"electric"},"damage_class":{"name":"physical"},"power":120,"accuracy":100,"pp":15,
+			"effect_entries":[{"short_effect":"User takes recoil damage.","language":{"name":"en"}}]}`,
+		`{"name":"growl","type":{"name":"normal"},"damage_class":{"name":"status"},"accuracy":100,"pp":40,
+			"effect_entries":[{"short_effect":"Lowers the target's Attack by one stage.","language":{"name":"en"}}]}`,
+	} {
+		m := decode[pokeapi.Move](t, move)
+		client.moves[m.Name] = m
+	}
+	ctx := Context{
+		Client: client,
+	}
+
+	cases := []struct {
+		name        string
+		parameters  []string
+		expectRows  []string // fields of each table row, in order
+		expectTitle string
+		expectError bool
+	}{
+		{
+			name:        "newest version group ordered by method then level",
+			parameters:  []string{"pikachu"},
+			expectTitle: "Moves for pikachu in scarlet-violet:",
+			expectRows: []string{
+				"level-up 1 growl normal status - 100 40 Lowers the target's Attack by one stage.",
+				"level-up 1 thunder-shock electric special 40 100 30 Has a 10% chance to paralyze the target.",
+				"level-up 6 quick-attack normal physical 40 100 30 Usually goes first.",
+				"machine - thunderbolt electric special 90 100 15 May paralyze the target.",
+				"egg - volt-tackle electric physical 120 100 15 User takes recoil damage.",
+			},
+		},
+		{
+			name:        "version group filter",
+			parameters:  []string{"pikachu", "--version-group", "red-blue"},
+			expectTitle: "Moves for pikachu in red-blue:",
+			expectRows: []string{
+				"level-up 1 thunder-shock electric special 40 100 30 Has a 10% chance to paralyze the target.",
+			},
+		},
+		{
+			name:        "method filter",
+			parameters:  []string{"pikachu", "--method=machine"},
+			expectTitle: "Moves for pikachu in scarlet-violet:",
+			expectRows: []string{
+				"machine - thunderbolt electric special 90 100 15 May paralyze the target.",
+			},
+		},
+		{name: "no matching moves", parameters: []string{"pikachu", "--method", "tutor"}, expectError: true},
+		{name: "unknown flag", parameters: []string{"pikachu", "--game", "red"}, expectError: true},
+		{name: "no pokemon", parameters: []string{}, expectError: true},
+		{name: "unknown pokemon", parameters: []string{"missingno"}, expectError: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx.Out = &buf
+
+			err := CommandMoves(&ctx, c.parameters)
+			if c.expectError {
+				if err == nil {
+					t.Errorf("expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+			if len(lines) != len(c.expectRows)+2 {
+				t.Fatalf("expected title, header and %v rows, got %q", len(c.expectRows), buf.String())
+			}
+			if lines[0] != c.expectTitle {
+				t.Errorf("expected title %q, got %q", c.expectTitle, lines[0])
+			}
+			if !strings.HasPrefix(lines[1], "METHOD") {
+				t.Errorf("expected table header, got %q", lines[1])
+			}
+			for i, expect := range c.expectRows {
+				if got := strings.Join(strings.Fields(lines[i+2]), " "); got != expect {
+					t.Errorf("row %v: expected %q, got %q", i, expect, got)
+				}
+			}
+		})
+	}
+
+	// A move that fails to load fails the command
+	delete(client.moves, "volt-tackle")
+	ctx.Out = io.Discard
+	if err := CommandMoves(&ctx, []string{"pikachu"}); !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected the move lookup error, got %v", err)
+	}
+}
+
+func TestCommandAbility(t *testing.T) {
+	client := newFakeAPI()
+	client.abilities["static"] = decode[pokeapi.Ability](t, `{"name":"static","effect_entries":[
+		{"effect":"Pokemon that make contact\nmay be paralyzed.","language":{"name":"en"}},
+		{"effect":"Peut paralyser au contact.","language":{"name":"fr"}}
+	],"pokemon":[
+		{"is_hidden":false,"pokemon":{"name":"pikachu"}},
+		{"is_hidden":true,"pokemon":{"name":"electrode"}}
+	]}`)
+	ctx := Context{
+		Client: client,
+	}
+
+	cases := []struct {
+		parameters     []string
+		language       string
+		expectContains string
+		expectError    bool
+	}{
+		{parameters: []string{"static"}, expectContains: "Name: static\nEffect:\n  Pokemon that make contact may be paralyzed.\n", expectError: false},
+		{parameters: []string{"static"}, language: "fr", expectContains: "Effect:\n  Peut paralyser au contact.\n", expectError: false},
+		{parameters: []string{"static"}, language: "ja", expectContains: "Effect:\n  Pokemon that make contact may be paralyzed.\n", expectError: false},
+		{parameters: []string{"Static"}, expectContains: "Pokemon:\n  - pikachu\n  - electrode (hidden)\n", expectError: false},
+		{parameters: []string{}, expectContains: "", expectError: true},
+		{parameters: []string{"static", "cling"}, expectContains: "", expectError: true},
+		{parameters: []string{"cloud-nine"}, expectContains: "", expectError: true},
+	}
+
+	for _, c := range cases {
 		var buf bytes.Buffer
-		io.Copy(&buf, r)
-		os.Stdout = old
+		ctx.Out = &buf
+		ctx.Language = c.language
+
+		err := CommandAbility(&ctx, c.parameters)
 
 		if c.expectError && err == nil {
 			t.Errorf("expected error but got nil")
@@ -297,3 +848,97 @@ func TestCommandCatch(t *testing.T) {
 		}
 	}
 }
+
+func TestCommandInspectAbilities(t *testing.T) {
+	ctx := Context{
+		Client: newFakeAPI(),
+		Pokedex: map[string]pokeapi.Pokemon{
+			"pikachu": decode[pokeapi.Pokemon](t, `{"name":"pikachu","abilities":[
+				{"ability":{"name":"static"},"is_hidden":false,"slot":1},
+				{"ability":{"name":"lightning-rod"},"is_hidden":true,"slot":3}
+			]}`),
+		},
+	}
+	var buf bytes.Buffer
+	ctx.Out = &buf
+
+	if err := CommandInspect(&ctx, []string{"pikachu"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := "Abilities:\n  - static\n  - lightning-rod (hidden)\n"
+	if !strings.Contains(buf.String(), expect) {
+		t.Errorf("expected output to contain %q, got %q", expect, buf.String())
+	}
+}
+
+func TestCommandInspectSpecies(t *testing.T) {
+	client := newFakeAPI()
+	client.species["pikachu"] = decode[pokeapi.PokemonSpecies](t, `{"name":"pikachu",
+		"genera":[{"genus":"Mouse Pokémon","language":{"name":"en"}},{"genus":"Pokémon Souris","language":{"name":"fr"}}],
+		"flavor_text_entries":[
+			{"flavor_text":"When several of\nthese POKéMON\fgather, their\nelectricity could\nbuild and cause\nlightning storms.","language":{"name":"en"},"version":{"name":"red"}},
+			{"flavor_text":"It keeps its tail\nraised to monitor\nits surroundings.","language":{"name":"en"},"version":{"name":"scarlet"}}
+		]}`)
+	ctx := Context{
+		Client: client,
+		Pokedex: map[string]pokeapi.Pokemon{
+			"pikachu":   decode[pokeapi.Pokemon](t, `{"name":"pikachu","species":{"name":"pikachu"}}`),
+			"missingno": decode[pokeapi.Pokemon](t, `{"name":"missingno","species":{"name":"missingno"}}`),
+		},
+	}
+
+	cases := []struct {
+		parameters     []string
+		language       string
+		expectContains []string
+		expectMissing  string
+	}{
+		{
+			parameters: []string{"pikachu"},
+			expectContains: []string{
+				"Name: pikachu\nGenus: Mouse Pokémon\n",
+				"Pokedex entry:\n  It keeps its tail raised to monitor its surroundings.\n",
+			},
+		},
+		{
+			parameters:     []string{"pikachu"},
+			language:       "fr",
+			expectContains: []string{"Genus: Pokémon Souris\n", "Pokedex entry:\n  It keeps its tail"},
+		},
+		{
+			// A species PokeAPI cannot find is left out
+			parameters:     []string{"missingno"},
+			expectContains: []string{"Name: missingno\n"},
+			expectMissing:  "Genus",
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		ctx.Out = &buf
+		ctx.Language = c.language
+
+		if err := CommandInspect(&ctx, c.parameters); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		for _, expect := range c.expectContains {
+			if !strings.Contains(buf.String(), expect) {
+				t.Errorf("expected output to contain %q, got %q", expect, buf.String())
+			}
+		}
+		if c.expectMissing != "" && strings.Contains(buf.String(), c.expectMissing) {
+			t.Errorf("expected output not to contain %q, got %q", c.expectMissing, buf.String())
+		}
+	}
+
+	// An interrupted inspect reports the cancellation instead of partial output
+	client.err = context.Canceled
+	var buf bytes.Buffer
+	ctx.Out = &buf
+	if err := CommandInspect(&ctx, []string{"pikachu"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output after cancellation, got %q", buf.String())
+	}
+}
@@ -0,0 +1,164 @@
+package savefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokeapi"
+)
+
+const (
+	// Schema version written by Save. Bump it and add a migration when the format changes.
+	CurrentVersion = 1
+	AppDir         = "pokedexcli"
+	FileName       = "pokedex.json"
+)
+
+// On-disk layout of a save file
+type File struct {
+	Version int                        `json:"version"`
+	SavedAt time.Time                  `json:"saved_at"`
+	Pokedex map[string]pokeapi.Pokemon `json:"pokedex"`
+}
+
+// Migrations upgrade raw save data from version n to version n+1
+var migrations = map[int]func(data []byte) ([]byte, error){
+	0: migrateV0,
+}
+
+// Returns the default save path, $XDG_DATA_HOME/pokedexcli/pokedex.json,
+// falling back to ~/.local/share when XDG_DATA_HOME is unset
+func DefaultPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding home directory: %v", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, AppDir, FileName), nil
+}
+
+// Load the Pokedex stored at path. A missing file is an empty Pokedex.
+func Load(path string) (map[string]pokeapi.Pokemon, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]pokeapi.Pokemon), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading save file %v: %v", path, err)
+	}
+
+	data, err = migrate(data)
+	if err != nil {
+		return nil, fmt.Errorf("error migrating save file %v: %v", path, err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error unmarshalling save file %v: %v", path, err)
+	}
+	if file.Pokedex == nil {
+		file.Pokedex = make(map[string]pokeapi.Pokemon)
+	}
+	return file.Pokedex, nil
+}
+
+// Save the Pokedex to path, replacing the previous file atomically
+func Save(path string, pokedex map[string]pokeapi.Pokemon) error {
+	if pokedex == nil {
+		pokedex = make(map[string]pokeapi.Pokemon)
+	}
+	file := File{
+		Version: CurrentVersion,
+		SavedAt: time.Now().UTC(),
+		Pokedex: pokedex,
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("error marshalling save file: %v", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creating save directory %v: %v", dir, err)
+	}
+
+	// Write to a temp file first so a crash never leaves a half-written save
+	tmp, err := os.CreateTemp(dir, FileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temp save file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing save file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error closing save file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error replacing save file %v: %v", path, err)
+	}
+	return nil
+}
+
+// Detects the version of raw save data and runs every migration up to CurrentVersion
+func migrate(data []byte) ([]byte, error) {
+	version, err := detectVersion(data)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("save version %v is newer than supported version %v", version, CurrentVersion)
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		migration, ok := migrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration from save version %v", v)
+		}
+		data, err = migration(data)
+		if err != nil {
+			return nil, fmt.Errorf("error migrating from version %v: %v", v, err)
+		}
+	}
+	return data, nil
+}
+
+// Files without a numeric "version" field are version 0
+func detectVersion(data []byte) (int, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, fmt.Errorf("error unmarshalling save file: %v", err)
+	}
+
+	raw, ok := fields["version"]
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		// a Pokemon named "version" in a version 0 save
+		return 0, nil
+	}
+	return version, nil
+}
+
+// Version 0 was a bare map of name -> Pokemon with no envelope
+func migrateV0(data []byte) ([]byte, error) {
+	var pokedex map[string]pokeapi.Pokemon
+	if err := json.Unmarshal(data, &pokedex); err != nil {
+		return nil, err
+	}
+	return json.Marshal(File{
+		Version: 1,
+		Pokedex: pokedex,
+	})
+}
@@ -0,0 +1,107 @@
+package savefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokeapi"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+
+	pokedex := map[string]pokeapi.Pokemon{
+		"pikachu":   {Name: "pikachu", Height: 4, Weight: 60},
+		"bulbasaur": {Name: "bulbasaur", Height: 7, Weight: 69},
+	}
+	if err := Save(path, pokedex); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(loaded) != len(pokedex) {
+		t.Fatalf("expected %v pokemon, got %v", len(pokedex), len(loaded))
+	}
+	if loaded["pikachu"].Weight != 60 {
+		t.Errorf("expected pikachu weight 60, got %v", loaded["pikachu"].Weight)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	loaded, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if loaded == nil || len(loaded) != 0 {
+		t.Errorf("expected empty pokedex, got %v", loaded)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	cases := []struct {
+		name        string
+		data        string
+		expectName  string
+		expectError bool
+	}{
+		{
+			name:       "version 0 bare map",
+			data:       `{"charmander":{"name":"charmander","height":6}}`,
+			expectName: "charmander",
+		},
+		{
+			name:       "version 1 envelope",
+			data:       `{"version":1,"saved_at":"2025-01-01T00:00:00Z","pokedex":{"squirtle":{"name":"squirtle"}}}`,
+			expectName: "squirtle",
+		},
+		{
+			name:        "future version",
+			data:        `{"version":99,"pokedex":{}}`,
+			expectError: true,
+		},
+		{
+			name:        "corrupt",
+			data:        `{"version":`,
+			expectError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			if err := os.WriteFile(path, []byte(c.data), 0o644); err != nil {
+				t.Fatalf("error writing fixture: %v", err)
+			}
+
+			loaded, err := Load(path)
+			if c.expectError {
+				if err == nil {
+					t.Errorf("expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := loaded[c.expectName]; !ok {
+				t.Errorf("expected %v in pokedex, got %v", c.expectName, loaded)
+			}
+		})
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/xdg")
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath returned error: %v", err)
+	}
+	expected := filepath.Join("/tmp/xdg", AppDir, FileName)
+	if path != expected {
+		t.Errorf("expected %v, got %v", expected, path)
+	}
+}
@@ -1,9 +1,82 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/evanwiseman/pokedexcli/internal/pokeapi"
+	"github.com/evanwiseman/pokedexcli/internal/pokecache"
 	"github.com/evanwiseman/pokedexcli/internal/repl"
 )
 
+// Environment variable used when --api-url is not set
+const apiURLEnv = "POKEDEX_API_URL"
+
 func main() {
-	repl.Start()
+	apiURL := flag.String("api-url", "", "PokeAPI base URL (env "+apiURLEnv+", default "+pokeapi.DefaultBaseURL+")")
+	rps := flag.Float64("rps", pokeapi.DefaultRequestsPerSecond, "max PokeAPI requests per second, 0 disables the limit")
+	burst := flag.Int("burst", pokeapi.DefaultBurst, "max burst of PokeAPI requests")
+	concurrency := flag.Int("concurrency", pokeapi.DefaultConcurrency, "max PokeAPI requests in flight for batch lookups")
+	diskCache := flag.Bool("disk-cache", true, "keep PokeAPI responses on disk between sessions")
+	offline := flag.Bool("offline", false, "serve every request from --bundle instead of PokeAPI")
+	bundlePath := flag.String("bundle", "", "bundle directory or .zip used by --offline, see 'bundle build'")
+	lang := flag.String("lang", pokeapi.DefaultLanguage, "language of Pokedex entries, e.g. en, fr, ja")
+	debug := flag.Bool("debug", false, "print debug output to stderr")
+	flag.Parse()
+
+	baseURL := pokeapi.DefaultBaseURL
+	if env := os.Getenv(apiURLEnv); env != "" {
+		baseURL = env
+	}
+	if *apiURL != "" {
+		baseURL = *apiURL
+	}
+
+	opts := []pokeapi.Option{
+		pokeapi.WithBaseURL(baseURL),
+		pokeapi.WithRateLimit(*rps, *burst),
+		pokeapi.WithConcurrency(*concurrency),
+	}
+	if *bundlePath != "" && !*offline {
+		fmt.Fprintln(os.Stderr, "error --bundle is only used with --offline")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *offline {
+		if *bundlePath == "" {
+			fmt.Fprintln(os.Stderr, "error --offline needs a --bundle directory or .zip")
+			flag.Usage()
+			os.Exit(2)
+		}
+		bundle, err := pokeapi.OpenBundle(*bundlePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		opts = append(opts, pokeapi.WithBundle(bundle))
+	} else if *diskCache {
+		disk, err := openDiskCache()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error opening disk cache, continuing without it: %v\n", err)
+		} else {
+			opts = append(opts, pokeapi.WithDiskCache(disk))
+		}
+	}
+	if *debug {
+		opts = append(opts, pokeapi.WithDebugLogger(log.New(os.Stderr, "debug: ", log.LstdFlags)))
+	}
+
+	client := pokeapi.NewClient(opts...)
+	defer client.Close()
+	repl.Start(client, *lang)
+}
+
+func openDiskCache() (*pokecache.DiskCache, error) {
+	dir, err := pokecache.DefaultDiskDir()
+	if err != nil {
+		return nil, err
+	}
+	return pokecache.NewDiskCache(dir, pokecache.DefaultDiskTTL, pokecache.DefaultDiskMaxBytes)
 }